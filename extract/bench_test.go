@@ -0,0 +1,122 @@
+package extract
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// benchmarkFixtures は、抽出処理の性能を計測する代表的なページの種類です。
+var benchmarkFixtures = []string{
+	"news",       // 段落中心のニュース記事
+	"docs",       // コードブロックを含むドキュメント
+	"listing",    // カード型の記事一覧
+	"tables",     // 表の多い統計ページ
+	"paragraphs", // 段落のみが大量に続く長文記事
+}
+
+// nopFetcher は、ベンチマークで Extractor を生成するためだけの Fetcher です。
+type nopFetcher struct{}
+
+func (nopFetcher) FetchBytes(ctx context.Context, url string) ([]byte, error) {
+	return nil, nil
+}
+
+// BenchmarkExtractContentText は、解析済みのドキュメントからの本文抽出 (extractContentText) を計測します。
+// extractContentText はノイズ除去でドキュメントを変更するため、各反復の前に計測外で解析し直します。
+func BenchmarkExtractContentText(b *testing.B) {
+	for _, name := range benchmarkFixtures {
+		b.Run(name, func(b *testing.B) {
+			html, err := os.ReadFile(filepath.Join("testdata", name+".html"))
+			if err != nil {
+				b.Fatal(err)
+			}
+			e, err := NewExtractor(nopFetcher{})
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			b.ReportAllocs()
+			for b.Loop() {
+				b.StopTimer()
+				doc, err := goquery.NewDocumentFromReader(bytes.NewReader(html))
+				if err != nil {
+					b.Fatal(err)
+				}
+				b.StartTimer()
+
+				if _, _, err := e.extractContentText(context.Background(), doc, "https://example.com/"+name); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkProcessGeneralElement は、段落などのテキスト要素ごとの整形処理を計測します。
+// processGeneralElement はドキュメントを変更しないため、解析は計測前に1度だけ行います。
+func BenchmarkProcessGeneralElement(b *testing.B) {
+	for _, name := range []string{"paragraphs", "docs"} {
+		b.Run(name, func(b *testing.B) {
+			html, err := os.ReadFile(filepath.Join("testdata", name+".html"))
+			if err != nil {
+				b.Fatal(err)
+			}
+			doc, err := goquery.NewDocumentFromReader(bytes.NewReader(html))
+			if err != nil {
+				b.Fatal(err)
+			}
+			e, err := NewExtractor(nopFetcher{})
+			if err != nil {
+				b.Fatal(err)
+			}
+			elements := doc.Find(textExtractionTags)
+
+			b.ReportAllocs()
+			for b.Loop() {
+				elements.Each(func(i int, s *goquery.Selection) {
+					e.processGeneralElement(s, nil)
+				})
+			}
+		})
+	}
+}
+
+// BenchmarkExtractMany は、ワーカー数ごとの複数ドキュメントの抽出スループットを計測します。
+// マルチコア環境ではワーカー数を増やすことで ns/op が短縮されます。
+func BenchmarkExtractMany(b *testing.B) {
+	var docs [][]byte
+	for range 4 {
+		for _, name := range benchmarkFixtures {
+			html, err := os.ReadFile(filepath.Join("testdata", name+".html"))
+			if err != nil {
+				b.Fatal(err)
+			}
+			docs = append(docs, html)
+		}
+	}
+
+	workerCounts := []int{1}
+	if n := runtime.GOMAXPROCS(0); n > 1 {
+		workerCounts = append(workerCounts, n)
+	}
+	for _, workers := range workerCounts {
+		b.Run("workers="+strconv.Itoa(workers), func(b *testing.B) {
+			e, err := NewExtractor(nopFetcher{}, WithConcurrentExtraction(workers))
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			b.ReportAllocs()
+			for b.Loop() {
+				e.ExtractMany(context.Background(), docs)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package extract
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// breadcrumbSeparators は、パンくずリストの項目として扱わない区切り記号です。
+var breadcrumbSeparators = map[string]bool{
+	">": true, "/": true, "›": true, "»": true, "|": true, "＞": true,
+}
+
+// breadcrumbLabelPrefixes は、パンくずリストの nav 要素の aria-label とみなす値の接頭辞です (小文字で比較します)。
+// "Breadcrumb" や "Breadcrumbs"、"breadcrumb navigation" のほか、主な言語の表記に一致します。
+var breadcrumbLabelPrefixes = []string{"breadcrumb", "パンくず", "fil d'ariane", "migas de pan", "brotkrümel", "面包屑"}
+
+// findBreadcrumbs はページのパンくずリストを上位の階層から順に返します。
+// JSON-LD の BreadcrumbList を優先し、無い場合はマイクロデータ、
+// aria-label がパンくずリストを示す nav 要素 (isBreadcrumbLabel) または .breadcrumb 要素の順に探索します。
+// パンくずは nav 要素内にあることが多いため、ノイズ除去の前に呼び出す必要があります。
+// jsonLD には jsonLDObjects で解析済みのオブジェクトを渡します。
+func findBreadcrumbs(doc *goquery.Document, jsonLD []map[string]any) []string {
+	if crumbs := jsonLDBreadcrumbs(jsonLD); len(crumbs) > 0 {
+		return crumbs
+	}
+	if crumbs := microdataBreadcrumbs(doc); len(crumbs) > 0 {
+		return crumbs
+	}
+
+	var crumbs []string
+	doc.Find("nav[aria-label], .breadcrumb, .breadcrumbs").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		if s.Is("nav[aria-label]") && !s.HasClass("breadcrumb") && !s.HasClass("breadcrumbs") &&
+			!isBreadcrumbLabel(s.AttrOr("aria-label", "")) {
+			return true
+		}
+		crumbs = breadcrumbItems(s)
+		return len(crumbs) == 0
+	})
+	return crumbs
+}
+
+// isBreadcrumbLabel は、aria-label の値がパンくずリストを示すかを大文字と小文字を区別せずに判定します。
+func isBreadcrumbLabel(label string) bool {
+	label = strings.ToLower(strings.TrimSpace(label))
+	for _, prefix := range breadcrumbLabelPrefixes {
+		if strings.HasPrefix(label, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonLDBreadcrumbs は JSON-LD の BreadcrumbList から項目名を position 順に取得します。
+func jsonLDBreadcrumbs(jsonLD []map[string]any) []string {
+	for _, object := range jsonLD {
+		if !hasJSONLDType(object, "BreadcrumbList") {
+			continue
+		}
+		elements, _ := object["itemListElement"].([]any)
+
+		type crumb struct {
+			position float64
+			name     string
+		}
+		var items []crumb
+		for _, element := range elements {
+			item, ok := element.(map[string]any)
+			if !ok {
+				continue
+			}
+			name, _ := item["name"].(string)
+			if name == "" {
+				// name が item オブジェクト側に記述される形式にも対応します
+				if inner, ok := item["item"].(map[string]any); ok {
+					name, _ = inner["name"].(string)
+				}
+			}
+			if name = collapseSpaces(name); name == "" {
+				continue
+			}
+			position, _ := item["position"].(float64)
+			items = append(items, crumb{position: position, name: name})
+		}
+		sort.SliceStable(items, func(i, j int) bool { return items[i].position < items[j].position })
+
+		crumbs := make([]string, 0, len(items))
+		for _, item := range items {
+			crumbs = append(crumbs, item.name)
+		}
+		if len(crumbs) > 0 {
+			return crumbs
+		}
+	}
+	return nil
+}
+
+// microdataBreadcrumbs は schema.org/BreadcrumbList のマイクロデータから項目名を取得します。
+func microdataBreadcrumbs(doc *goquery.Document) []string {
+	var crumbs []string
+	doc.Find(`[itemtype*="schema.org/BreadcrumbList"]`).EachWithBreak(func(i int, list *goquery.Selection) bool {
+		list.Find(`[itemprop="itemListElement"]`).Each(func(j int, item *goquery.Selection) {
+			if name := collapseSpaces(item.Find(`[itemprop="name"]`).First().Text()); name != "" {
+				crumbs = append(crumbs, name)
+			}
+		})
+		return len(crumbs) == 0
+	})
+	return crumbs
+}
+
+// breadcrumbItems はパンくずリスト要素から項目のテキストを取得します。
+// li 要素があればその内容を、無ければ a 要素の内容を項目として扱います。
+func breadcrumbItems(s *goquery.Selection) []string {
+	items := s.Find("li")
+	if items.Length() == 0 {
+		items = s.Find("a")
+	}
+
+	var crumbs []string
+	items.Each(func(i int, item *goquery.Selection) {
+		text := collapseSpaces(item.Text())
+		if text != "" && !breadcrumbSeparators[text] {
+			crumbs = append(crumbs, text)
+		}
+	})
+	return crumbs
+}
+
+// collapseSpaces は前後の空白を取り除き、連続する空白を1つにまとめます。
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
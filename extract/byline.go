@@ -0,0 +1,87 @@
+package extract
+
+import (
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// publishedMetaKeys は、公開日時を宣言する meta 要素のキーを優先順に並べたものです。
+var publishedMetaKeys = []string{"article:published_time", "og:published_time", "datePublished", "pubdate"}
+
+// findPublishedAt は、meta 要素、JSON-LD の datePublished、time 要素の順に公開日時を探します。
+// time 要素は itemprop="datePublished" または pubdate 属性を持つものを優先します。
+// jsonLD には jsonLDObjects で解析済みのオブジェクトを渡します。解析できる値が見つからない場合は nil を返します。
+func findPublishedAt(doc *goquery.Document, jsonLD []map[string]any) *time.Time {
+	for _, key := range publishedMetaKeys {
+		if t, ok := parseDateTime(metaContent(doc, key)); ok {
+			return &t
+		}
+	}
+
+	for _, object := range jsonLD {
+		if value, ok := object["datePublished"].(string); ok {
+			if t, ok := parseDateTime(value); ok {
+				return &t
+			}
+		}
+	}
+
+	for _, selector := range []string{`time[itemprop="datePublished"], time[pubdate]`, "time[datetime]"} {
+		if t, ok := parseDateTime(doc.Find(selector).First().AttrOr("datetime", "")); ok {
+			return &t
+		}
+	}
+	return nil
+}
+
+// findAuthor は、meta name="author"、rel="author" のリンク、JSON-LD の author の順に著者名を探します。
+// jsonLD には jsonLDObjects で解析済みのオブジェクトを渡します。見つからない場合は空文字列を返します。
+func findAuthor(doc *goquery.Document, jsonLD []map[string]any) string {
+	if author := metaContent(doc, "author"); author != "" {
+		return author
+	}
+
+	var author string
+	doc.Find("a[rel]").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		for _, token := range strings.Fields(strings.ToLower(s.AttrOr("rel", ""))) {
+			if token == "author" {
+				author = collapseSpaces(s.Text())
+				break
+			}
+		}
+		return author == ""
+	})
+	if author != "" {
+		return author
+	}
+
+	for _, object := range jsonLD {
+		if names := jsonLDNames(object["author"]); len(names) > 0 {
+			return strings.Join(names, ", ")
+		}
+	}
+	return ""
+}
+
+// jsonLDNames は、JSON-LD の author などの値から名前を取り出します。
+// 値は文字列、name を持つオブジェクト、またはそれらの配列のいずれでも構いません。
+func jsonLDNames(value any) []string {
+	var names []string
+	switch v := value.(type) {
+	case string:
+		if name := collapseSpaces(v); name != "" {
+			names = append(names, name)
+		}
+	case map[string]any:
+		if name, ok := v["name"].(string); ok {
+			names = append(names, jsonLDNames(name)...)
+		}
+	case []any:
+		for _, item := range v {
+			names = append(names, jsonLDNames(item)...)
+		}
+	}
+	return names
+}
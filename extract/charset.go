@@ -0,0 +1,73 @@
+package extract
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"mime"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html/charset"
+)
+
+// charsetFromContentType は Content-Type ヘッダーの charset パラメータを返します。
+// 宣言が無い場合や解析できない場合は空文字列を返します。
+func charsetFromContentType(contentType string) string {
+	if contentType == "" {
+		return ""
+	}
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(params["charset"])
+}
+
+// metaCharsetPrescanBytes は meta 要素の charset 宣言を探すHTML先頭のバイト数です。
+// HTML仕様の prescan と同じく、宣言は先頭 1024 バイト以内にあるものとみなします。
+const metaCharsetPrescanBytes = 1024
+
+// metaCharsetPattern は <meta charset="..."> と
+// <meta http-equiv="Content-Type" content="text/html; charset=..."> の charset を捉えます。
+var metaCharsetPattern = regexp.MustCompile(`(?i)<meta[^>]+charset\s*=\s*["']?\s*([a-z0-9_:.\-]+)`)
+
+// charsetFromMeta は、HTML先頭の meta 要素で宣言された charset を返します。
+// 宣言が無い場合は空文字列を返します。
+func charsetFromMeta(prefix []byte) string {
+	m := metaCharsetPattern.FindSubmatch(prefix)
+	if m == nil {
+		return ""
+	}
+	return string(m[1])
+}
+
+// decodeReader は、強制指定の文字コード、Content-Type で宣言された charset、
+// HTML先頭の meta 要素で宣言された charset の順に参照し、最初に得られた文字コードから
+// reader を UTF-8 に変換します。いずれも無い場合は UTF-8 とみなして reader をそのまま返します。
+func (e *Extractor) decodeReader(reader io.Reader, contentType string) (io.Reader, error) {
+	if e.forcedCharset != "" {
+		decoded, err := charset.NewReaderLabel(e.forcedCharset, reader)
+		if err != nil {
+			return nil, fmt.Errorf("文字コード %q を扱えません: %w", e.forcedCharset, err)
+		}
+		return decoded, nil
+	}
+
+	label := charsetFromContentType(contentType)
+	if label == "" {
+		// Content-Type が得られない場合 (Fetcher 経由の取得など) は meta 要素の宣言を探します
+		buffered := bufio.NewReaderSize(reader, metaCharsetPrescanBytes)
+		prefix, _ := buffered.Peek(metaCharsetPrescanBytes)
+		reader = buffered
+		if label = charsetFromMeta(prefix); label == "" {
+			return reader, nil
+		}
+	}
+	decoded, err := charset.NewReaderLabel(label, reader)
+	if err != nil {
+		// 未知の charset が宣言されている場合は変換せずに解析します
+		return reader, nil
+	}
+	return decoded, nil
+}
@@ -0,0 +1,150 @@
+package extract
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// ContentDetection はメインコンテンツの特定方法を表します。
+type ContentDetection int
+
+const (
+	// ContentDetectionSelectors は、既定 (または WithMainContentSelectors で指定) のセレクターに
+	// 最初に一致した要素をメインコンテンツとします (デフォルト)。
+	// 一致しない場合は header や footer などを除いたページ全体を対象とします。
+	ContentDetectionSelectors ContentDetection = iota
+	// ContentDetectionFallback は、セレクターに一致する要素が無い場合に、
+	// ページ全体ではなくスコアリングで選んだ要素をメインコンテンツとします。
+	ContentDetectionFallback
+	// ContentDetectionScoring は、セレクターを使わずに常にスコアリングでメインコンテンツを選びます。
+	// Mozilla Readability と同様に、段落の文字数・リンク密度・タグの種類と
+	// class / id 属性から要素を採点し、最も高いスコアの要素を採用します。
+	ContentDetectionScoring
+)
+
+const (
+	// scoringMinParagraphLength は、スコアリングで段落として数える最小文字数 (rune 数) です。
+	scoringMinParagraphLength = 25
+	// scoringClassWeight は、class / id 属性が本文らしい、またはノイズらしい場合の加減点です。
+	scoringClassWeight = 25
+)
+
+var (
+	// positiveContentPattern は本文を示唆する class / id 属性の値です。
+	positiveContentPattern = regexp.MustCompile(`(?i)article|body|content|entry|main|page|post|text|blog|story`)
+	// negativeContentPattern はノイズを示唆する class / id 属性の値です。
+	negativeContentPattern = regexp.MustCompile(`(?i)comment|footer|sidebar|nav|menu|related|share|social|sponsor|widget|banner|promo|popup|\bad-|\bads?\b`)
+)
+
+// findMainContentByScore は、段落 (p, pre, td, blockquote) の得点を親要素と祖父母要素に加算し、
+// リンク密度で割り引いた得点が最も高い要素を返します。段落が見つからない場合は nil を返します。
+func findMainContentByScore(doc *goquery.Document) *goquery.Selection {
+	scores := make(map[*html.Node]float64)
+	var candidates []*html.Node
+
+	addScore := func(node *html.Node, score float64) {
+		if node == nil || node.Type != html.ElementNode {
+			return
+		}
+		if _, ok := scores[node]; !ok {
+			scores[node] = initialContentScore(node)
+			candidates = append(candidates, node)
+		}
+		scores[node] += score
+	}
+
+	doc.Find("p, pre, td, blockquote").Each(func(i int, s *goquery.Selection) {
+		node := s.Get(0)
+		content := strings.TrimSpace(s.Text())
+		length := utf8.RuneCountInString(content)
+		if length < scoringMinParagraphLength {
+			return
+		}
+
+		// 基本点 1 に、読点の数と100文字ごとの加点 (最大3点) を加えます
+		score := 1 + float64(strings.Count(content, ",")+strings.Count(content, "、")+strings.Count(content, "，"))
+		score += float64(min(length/100, 3))
+
+		addScore(node.Parent, score)
+		if node.Parent != nil {
+			addScore(node.Parent.Parent, score/2)
+		}
+	})
+
+	var best *html.Node
+	var bestScore float64
+	for _, node := range candidates {
+		score := scores[node] * (1 - linkDensity(node))
+		if best == nil || score > bestScore {
+			best, bestScore = node, score
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	return doc.FindNodes(best)
+}
+
+// initialContentScore は、タグの種類と class / id 属性から要素の初期得点を返します。
+func initialContentScore(node *html.Node) float64 {
+	var score float64
+	switch node.Data {
+	case "div", "article", "main", "section":
+		score = 5
+	case "pre", "td", "blockquote":
+		score = 3
+	case "address", "ol", "ul", "dl", "dd", "dt", "li", "form":
+		score = -3
+	case "h1", "h2", "h3", "h4", "h5", "h6", "th":
+		score = -5
+	}
+
+	for _, key := range []string{"class", "id"} {
+		value := attrValue(node, key)
+		if value == "" {
+			continue
+		}
+		if negativeContentPattern.MatchString(value) {
+			score -= scoringClassWeight
+		}
+		if positiveContentPattern.MatchString(value) {
+			score += scoringClassWeight
+		}
+	}
+	return score
+}
+
+// linkDensity は、node のテキストのうち a 要素内の文字が占める割合を返します。
+func linkDensity(node *html.Node) float64 {
+	total, linked := textLengths(node, false)
+	if total == 0 {
+		return 0
+	}
+	return float64(linked) / float64(total)
+}
+
+// textLengths は、node の子孫のテキストの文字数と、そのうち a 要素内の文字数を返します。
+func textLengths(node *html.Node, inLink bool) (total, linked int) {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		switch child.Type {
+		case html.TextNode:
+			n := utf8.RuneCountInString(strings.TrimSpace(child.Data))
+			total += n
+			if inLink {
+				linked += n
+			}
+		case html.ElementNode:
+			if child.Data == "script" || child.Data == "style" {
+				continue
+			}
+			t, l := textLengths(child, inLink || child.Data == "a")
+			total += t
+			linked += l
+		}
+	}
+	return total, linked
+}
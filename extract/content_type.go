@@ -0,0 +1,56 @@
+package extract
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+)
+
+// ErrUnsupportedContentType は、WithAllowedContentTypes で許可されていない Content-Type の
+// コンテンツを抽出しようとした場合に返されるエラーです。
+var ErrUnsupportedContentType = errors.New("サポートされていない Content-Type です")
+
+// mediaTypeOf は Content-Type ヘッダーから小文字のメディアタイプを取り出します。
+func mediaTypeOf(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		// 不正なパラメータを含むヘッダーでも判定できるよう、セミコロンより前を使用します
+		mediaType, _, _ = strings.Cut(contentType, ";")
+	}
+	return strings.ToLower(strings.TrimSpace(mediaType))
+}
+
+// checkContentType は、Content-Type が抽出対象として許可されているかを確認し、メディアタイプを返します。
+// 許可リストが未設定の場合や Content-Type が不明な場合は、従来どおりHTMLとして扱います。
+func (e *Extractor) checkContentType(contentType string) (string, error) {
+	mediaType := mediaTypeOf(contentType)
+	if len(e.allowedContentTypes) == 0 || mediaType == "" {
+		return "text/html", nil
+	}
+	for _, allowed := range e.allowedContentTypes {
+		if mediaType == allowed {
+			return mediaType, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnsupportedContentType, mediaType)
+}
+
+// extractPlainText は text/plain のコンテンツを整形済みテキストとしてそのまま返します。
+// HTMLとして解析しないため、改行やインデントは保持されます。
+func (e *Extractor) extractPlainText(reader io.Reader, contentType string) (text string, hasBodyFound bool, err error) {
+	reader, err = e.decodeReader(reader, contentType)
+	if err != nil {
+		return "", false, err
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return "", false, fmt.Errorf("テキストの読み込みに失敗しました: %w", err)
+	}
+	text = strings.TrimSpace(strings.ReplaceAll(string(data), "\r\n", "\n"))
+	if text == "" {
+		return "", false, ErrNoContent
+	}
+	return text, true, nil
+}
@@ -0,0 +1,115 @@
+package extract
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/forPelevin/gomoji"
+	"github.com/rivo/uniseg"
+)
+
+// EmojiHandling は抽出テキスト中の絵文字の扱いを表します。
+type EmojiHandling int
+
+const (
+	// EmojiKeep は絵文字をそのまま残します (デフォルト)。
+	EmojiKeep EmojiHandling = iota
+	// EmojiStrip は絵文字を取り除きます。ZWJ シーケンスや肌の色の修飾子を含む
+	// 書記素クラスタ単位で除去するため、構成要素のコードポイントが残ることはありません。
+	// ©、®、™ などテキストとして表示される記号は取り除きません。
+	EmojiStrip
+	// EmojiDescribe は絵文字を ":thumbs_up:" のようなショートコードに置き換えます。
+	// ショートコードを決定できない絵文字は取り除きます。
+	EmojiDescribe
+)
+
+// nonShortcodeChars はショートコードに使用しない文字の並びです。
+var nonShortcodeChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// emojiVersionPrefix は UnicodeName の先頭に付く絵文字バージョン (例: "E1.0 ") です。
+var emojiVersionPrefix = regexp.MustCompile(`^E\d+(\.\d+)? `)
+
+// applyEmojiHandling は設定に従って s の絵文字を処理します。
+func (e *Extractor) applyEmojiHandling(s string) string {
+	if e.emojiHandling == EmojiKeep {
+		return s
+	}
+
+	var builder strings.Builder
+	state := -1
+	for len(s) > 0 {
+		var cluster string
+		var width int
+		cluster, s, width, state = uniseg.FirstGraphemeClusterInString(s, state)
+
+		if !isEmojiPresentation(cluster, width) {
+			builder.WriteString(cluster)
+			continue
+		}
+		emoji, ok := lookupEmoji(cluster)
+		if !ok {
+			builder.WriteString(cluster)
+			continue
+		}
+		if e.emojiHandling == EmojiDescribe {
+			if code := emojiShortcode(emoji); code != "" {
+				builder.WriteString(":" + code + ":")
+			}
+		}
+	}
+	return builder.String()
+}
+
+// isEmojiPresentation は、書記素クラスタが絵文字として表示されるかを判定します。
+// ©、™、▶ のように絵文字のデータに含まれていても既定でテキストとして表示される記号は、
+// 異体字セレクター (U+FE0F) を伴わない限り本文の一部として残します。
+// uniseg は Emoji_Presentation の文字、U+FE0F を伴う文字、ZWJ・肌の色の修飾子・国旗のシーケンスを
+// 幅2として数えるため、その幅で判定します。幅1として数えられるキーキャップ (U+20E3) は個別に扱います。
+func isEmojiPresentation(cluster string, width int) bool {
+	return width == 2 || strings.ContainsRune(cluster, '\u20e3')
+}
+
+// lookupEmoji は書記素クラスタが絵文字であればその情報を返します。
+// 異体字セレクターの有無による表記揺れを吸収して照合します。
+func lookupEmoji(cluster string) (gomoji.Emoji, bool) {
+	if emoji, err := gomoji.GetInfo(cluster); err == nil {
+		return emoji, true
+	}
+	withoutSelectors := strings.Map(func(r rune) rune {
+		if unicode.In(r, unicode.Variation_Selector) {
+			return -1
+		}
+		return r
+	}, cluster)
+	if withoutSelectors == cluster || withoutSelectors == "" {
+		return gomoji.Emoji{}, false
+	}
+	emoji, err := gomoji.GetInfo(withoutSelectors)
+	return emoji, err == nil
+}
+
+// emojiShortcode は絵文字の英語名から "thumbs_up_medium_skin_tone" のようなショートコードを生成します。
+func emojiShortcode(emoji gomoji.Emoji) string {
+	for _, name := range []string{emoji.Slug, emojiVersionPrefix.ReplaceAllString(emoji.UnicodeName, "")} {
+		// 英数字以外の名前 (一部の独自データ) はショートコードに使用しません
+		if !isASCII(name) {
+			continue
+		}
+		code := strings.Trim(nonShortcodeChars.ReplaceAllString(strings.ToLower(name), "_"), "_")
+		if code != "" {
+			return code
+		}
+	}
+	return ""
+}
+
+// isASCII は s が ASCII 文字のみで構成されているかを判定します。
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] > unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,171 @@
+package extract
+
+import (
+	"encoding/csv"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// headingPartPattern は、見出しのパーツ ("## 概要" など) の先頭に一致します。
+// "#hashtag" のように空白が続かない "#" で始まる段落は見出しとみなしません。
+var headingPartPattern = regexp.MustCompile(`^#{1,6} `)
+
+// excerptEllipsis は、文の途中で切り詰めた抜粋の末尾に付加する省略記号です。
+const excerptEllipsis = "…"
+
+// Excerpt は、抽出済みのテキストから maxRunes 文字 (rune 数) 以内の抜粋を生成します。
+// タイトル、見出し、表、コードブロック、frontmatter のパーツを読み飛ばし、最初の本文の段落から順に採用します。
+// 予算に収まらない段落は文の区切り (「。」「！」「？」や ". " など) で切り詰め、
+// 最初の1文すら収まらない場合に限り、文の途中で切り詰めて省略記号を付加します。
+// キャッシュした抽出結果にも適用できるよう、Extractor に依存しない関数として提供します。
+// タイトルと表題は既定の接頭辞 (DefaultTitlePrefix / DefaultTableCaptionPrefix) でのみ判別するため、
+// WithTitlePrefix などで接頭辞を変更した場合は ExcerptFromResult を使用してください。
+// 表は2行以上のパイプ区切り (TableStylePlain / TableStyleMarkdown / TableStyleAligned) または
+// CSV (TableStyleCSV) のパーツとして判別します。本文の "Tokyo | Osaka" のような1行の段落と区別できないため、
+// 1行だけの表は本文として扱います。表を確実に除外する必要がある場合も ExcerptFromResult を使用してください。
+func Excerpt(text string, maxRunes int) string {
+	var paragraphs []string
+	for _, part := range strings.Split(text, "\n\n") {
+		if part = strings.TrimSpace(part); isProsePart(part) {
+			paragraphs = append(paragraphs, part)
+		}
+	}
+	return excerptFromParagraphs(paragraphs, maxRunes)
+}
+
+// ExcerptFromResult は、FetchAndExtract の結果の本文の段落 (ExtractionResult.Paragraphs) から
+// Excerpt と同じ規則で maxRunes 文字以内の抜粋を生成します。
+// テキストを再解析しないため、接頭辞の設定や "#" で始まる段落の有無に左右されません。
+func ExcerptFromResult(result *ExtractionResult, maxRunes int) string {
+	if result == nil {
+		return ""
+	}
+	return excerptFromParagraphs(result.Paragraphs, maxRunes)
+}
+
+// excerptFromParagraphs は、本文の段落を先頭から順に maxRunes 文字以内に収まるまで採用します。
+func excerptFromParagraphs(paragraphs []string, maxRunes int) string {
+	if maxRunes <= 0 {
+		return ""
+	}
+
+	const separator = "\n\n"
+	var selected []string
+	remaining := maxRunes
+	for _, part := range paragraphs {
+		if part == "" {
+			continue
+		}
+
+		if len(selected) > 0 {
+			remaining -= utf8.RuneCountInString(separator)
+			if remaining <= 0 {
+				break
+			}
+		}
+
+		if n := utf8.RuneCountInString(part); n <= remaining {
+			selected = append(selected, part)
+			remaining -= n
+			continue
+		}
+
+		if cut := cutAtSentence(part, remaining); cut != "" {
+			selected = append(selected, cut)
+		} else if len(selected) == 0 {
+			selected = append(selected, truncateRunes(part, maxRunes-utf8.RuneCountInString(excerptEllipsis))+excerptEllipsis)
+		}
+		break
+	}
+	return strings.Join(selected, separator)
+}
+
+// isProsePart は、パーツが抜粋に使用できる本文の段落であるかを判定します。
+func isProsePart(part string) bool {
+	switch {
+	case part == "":
+		return false
+	case strings.HasPrefix(part, DefaultTitlePrefix), strings.HasPrefix(part, DefaultTableCaptionPrefix):
+		return false
+	case headingPartPattern.MatchString(part), strings.HasPrefix(part, "```"), strings.HasPrefix(part, "---\n"):
+		// 見出し、コードブロック、frontmatter
+		return false
+	}
+	return !isTablePart(part)
+}
+
+// isTablePart は、TableStyle のいずれかの形式で出力された2行以上のパーツを表とみなします。
+func isTablePart(part string) bool {
+	lines := strings.Split(part, "\n")
+	if len(lines) < 2 {
+		return false
+	}
+	return isPipeTable(lines) || isCSVTable(part)
+}
+
+// isPipeTable は、すべての行がセル区切り " | " を含むか "|" で始まるかを判定します。
+func isPipeTable(lines []string) bool {
+	for _, line := range lines {
+		if !strings.Contains(line, " | ") && !strings.HasPrefix(line, "|") {
+			return false
+		}
+	}
+	return true
+}
+
+// isCSVTable は、パーツが2列以上で列数の揃った CSV として解釈できるかを判定します。
+// encoding/csv の出力はカンマの後に空白を入れないため、", " で区切られた文章は表とみなしません。
+func isCSVTable(part string) bool {
+	records, err := csv.NewReader(strings.NewReader(part)).ReadAll()
+	if err != nil || len(records) < 2 || len(records[0]) < 2 {
+		return false
+	}
+	for _, record := range records {
+		for _, field := range record {
+			if strings.HasPrefix(field, " ") {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// cutAtSentence は、paragraph の先頭から maxRunes 文字以内で最後の文の区切りまでを返します。
+// 区切りが見つからない場合は空文字列を返します。
+func cutAtSentence(paragraph string, maxRunes int) string {
+	runes := []rune(paragraph)
+	end := 0
+	for i := 0; i < len(runes) && i < maxRunes; i++ {
+		if isSentenceEnd(runes, i) {
+			end = i + 1
+		}
+	}
+	return strings.TrimSpace(string(runes[:end]))
+}
+
+// isSentenceEnd は runes[i] が文末の記号であるかを判定します。
+// 半角のピリオドなどは小数点や略語と区別するため、直後が空白または末尾の場合のみ文末とみなします。
+func isSentenceEnd(runes []rune, i int) bool {
+	switch runes[i] {
+	case '。', '！', '？', '．':
+		return true
+	case '.', '!', '?':
+		return i+1 == len(runes) || unicode.IsSpace(runes[i+1])
+	default:
+		return false
+	}
+}
+
+// truncateRunes は s を先頭から n 文字 (rune 数) までに切り詰めます。
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if n <= 0 {
+		return ""
+	}
+	if n >= len(runes) {
+		return s
+	}
+	return strings.TrimSpace(string(runes[:n]))
+}
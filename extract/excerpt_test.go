@@ -0,0 +1,108 @@
+package extract_test
+
+import (
+	"context"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/shouni/go-web-exact/v2/extract"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExcerpt(t *testing.T) {
+	text := "【記事タイトル】 新製品発表\n\n" +
+		"## 概要\n\n" +
+		"本日、新しい製品を発表しました。価格は未定です。詳細は後日お知らせします。\n\n" +
+		"名前 | 価格\nA | 100\n\n" +
+		"```\ncode()\n```\n\n" +
+		"二番目の段落です。"
+
+	testCases := []struct {
+		name     string
+		maxRunes int
+		expected string
+	}{
+		{
+			name:     "whole_prose_fits",
+			maxRunes: 100,
+			expected: "本日、新しい製品を発表しました。価格は未定です。詳細は後日お知らせします。\n\n二番目の段落です。",
+		},
+		{
+			name:     "cut_at_japanese_sentence_boundary",
+			maxRunes: 25,
+			expected: "本日、新しい製品を発表しました。価格は未定です。",
+		},
+		{
+			name:     "first_sentence_too_long",
+			maxRunes: 8,
+			expected: "本日、新しい製…",
+		},
+		{name: "zero_budget", maxRunes: 0, expected: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := extract.Excerpt(text, tc.maxRunes)
+
+			assert.Equal(t, tc.expected, got)
+			assert.LessOrEqual(t, utf8.RuneCountInString(got), max(tc.maxRunes, 0))
+		})
+	}
+
+	t.Run("csv_table_is_skipped", func(t *testing.T) {
+		text := "name,price\nApple,100\n\nReal prose follows the table, as usual.\nIt continues here, too."
+
+		assert.Equal(t, "Real prose follows the table, as usual.\nIt continues here, too.", extract.Excerpt(text, 200))
+	})
+
+	t.Run("single_line_pipe_prose_is_kept", func(t *testing.T) {
+		text := "Tokyo | Osaka\n\nThe route runs between both cities."
+
+		assert.Equal(t, "Tokyo | Osaka\n\nThe route runs between both cities.", extract.Excerpt(text, 200))
+	})
+
+	t.Run("english_sentences_and_decimals", func(t *testing.T) {
+		got := extract.Excerpt("Version 2.5 ships today. It is faster. More below.", 40)
+
+		assert.Equal(t, "Version 2.5 ships today. It is faster.", got)
+	})
+
+	t.Run("no_prose", func(t *testing.T) {
+		assert.Empty(t, extract.Excerpt("【記事タイトル】 Only title", 50))
+	})
+
+	t.Run("hashtag_paragraph_is_prose", func(t *testing.T) {
+		assert.Equal(t, "#golang のリリースノートを読みました。", extract.Excerpt("## 見出し\n\n#golang のリリースノートを読みました。", 50))
+	})
+}
+
+func TestExcerptFromResult(t *testing.T) {
+	html := `<html><head><title>Hello</title></head><body><article>
+		<h2>Overview</h2>
+		<p>#release notes are published every month on the project blog.</p>
+		<table><tr><td>a</td><td>b</td></tr></table>
+		<p>The second paragraph explains the upgrade procedure.</p>
+	</article></body></html>`
+
+	extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html}, extract.WithTitlePrefix("Title: "))
+	assert.NoError(t, err)
+
+	result, err := extractor.FetchAndExtract(context.Background(), "https://example.com/release")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "#release notes are published every month on the project blog.", extract.ExcerptFromResult(result, 70))
+	assert.Empty(t, extract.ExcerptFromResult(nil, 70))
+
+	t.Run("title_only_result_has_no_excerpt", func(t *testing.T) {
+		extractor, err := extract.NewExtractor(
+			&MockFetcher{htmlContent: `<html><head><title>Hello</title></head><body></body></html>`},
+			extract.WithTitlePrefix("Title: "),
+		)
+		assert.NoError(t, err)
+
+		result, err := extractor.FetchAndExtract(context.Background(), "https://example.com/title")
+
+		assert.NoError(t, err)
+		assert.Empty(t, extract.ExcerptFromResult(result, 50))
+	})
+}
@@ -5,89 +5,383 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/url"
+	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/shouni/go-utils/text"
 	"golang.org/x/net/html"
+	"golang.org/x/text/unicode/norm"
 
 	"github.com/shouni/go-web-exact/v2/ports"
 )
 
 // Extractor は、Fetcher を使ってコンテンツ抽出プロセスを管理します。
 type Extractor struct {
-	fetcher ports.Fetcher
+	fetcher               ports.Fetcher
+	extractInlineJSON     bool
+	descriptionFallback   bool
+	allowTitleOnly        bool
+	headingMinLengths     map[int]int
+	outputFormat          OutputFormat
+	frontmatter           bool
+	forcedCharset         string
+	followIframes         bool
+	keepShortLines        bool
+	normalizeUnicode      bool
+	extractTimes          bool
+	tableStyle            TableStyle
+	tableStyleSet         bool // WithTableStyle で明示的に指定された場合は true
+	fallbackRelaxed       bool
+	maxHTMLBytes          int
+	titleSources          []string
+	stripSiteSuffix       bool
+	extractBreadcrumbs    bool
+	customDropPhrases     []string
+	defaultDropPhrases    bool
+	dropPhrasesIgnoreCase bool
+	dropPhrases           []dropPhrase // prepareDropPhrases で正規化した除去対象の定型文
+	minTableRows          int
+	minTableColumns       int
+	extractWorkers        int
+	allowedContentTypes   []string
+	detectSoft404         bool
+	preserveCodeIndent    bool
+	emojiHandling         EmojiHandling
+	maxParagraphs         int
+	stopAtMaxParagraphs   bool
+	soft404Keywords       []string
+	noiseSelectors        string // 空の場合は既定の noiseSelectors を使用
+	mainContentSelectors  string // 空の場合は既定の mainContentSelectors を使用
+	contentDetection      ContentDetection
+	maxLinkDensity        float64
+	readingSpeed          int
+	excludeCodeFromStats  bool
+	titlePrefix           string
+	tableCaptionPrefix    string
+	relaxedThresholds     bool // 緩和した閾値で2回目の収集を行う複製でのみ true
 }
 
 // NewExtractor は、新しいExtractorのインスタンスを生成します。
-func NewExtractor(fetcher ports.Fetcher) (*Extractor, error) {
+func NewExtractor(fetcher ports.Fetcher, opts ...Option) (*Extractor, error) {
 	if fetcher == nil {
 		return nil, fmt.Errorf("extract.NewExtractor: Fetcher cannot be nil")
 	}
-	return &Extractor{
-		fetcher: fetcher,
-	}, nil
+	e := &Extractor{
+		fetcher:            fetcher,
+		maxLinkDensity:     DefaultMaxLinkDensity,
+		titlePrefix:        DefaultTitlePrefix,
+		tableCaptionPrefix: DefaultTableCaptionPrefix,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	e.prepareDropPhrases()
+	return e, nil
+}
+
+// Close は、Fetcher が io.Closer を実装している場合にそのリソースを解放します。
+// コネクションプールやバックグラウンド処理を持つ Fetcher を長時間稼働するサービスで使う場合に呼び出してください。
+func (e *Extractor) Close() error {
+	if closer, ok := e.fetcher.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
 }
 
 // ----------------------------------------------------------------------
 // 定数定義 (解析関連のみ)
 // ----------------------------------------------------------------------
 const (
-	MinParagraphLength   = 20
-	MinHeadingLength     = 3
-	mainContentSelectors = "article, main, div[role='main'], #main, #content, .post-content, .article-body, .entry-content, .markdown-body, .readme"
-	noiseSelectors       = ".related-posts, .social-share, .comments, .ad-banner, .advertisement"
+	// MinParagraphLength と MinHeadingLength は、段落と見出しを本文として採用する最小文字数 (rune 数) です。
+	// この値を超える文字数の要素のみを採用します。
+	MinParagraphLength          = 20
+	MinHeadingLength            = 3
+	defaultMainContentSelectors = "article, main, div[role='main'], #main, #content, .post-content, .article-body, .entry-content, .markdown-body, .readme"
+	defaultNoiseSelectors       = ".related-posts, .social-share, .comments, .ad-banner, .advertisement"
+
+	// DefaultMaxLinkDensity は、段落とリスト項目を本文として採用するリンク密度
+	// (要素の文字数に対する a 要素内の文字数の割合) の既定の上限です。
+	DefaultMaxLinkDensity = 0.5
 
 	// textExtractionTags は本文抽出に使用するHTMLタグを定義します。
 	textExtractionTags = "p, h1, h2, h3, h4, h5, h6, li, blockquote"
 
-	titlePrefix        = "【記事タイトル】 "
-	tableCaptionPrefix = "【表題】 "
+	// shortLineRunLength は、短い行の連続とみなす同種の兄弟要素の最小数です。
+	shortLineRunLength = 3
+
+	// lineBreakMarker は、テキストの走査で br 要素の位置に書き込む目印 (U+2028 LINE SEPARATOR) です。
+	// HTMLソース中の改行と区別するために使用し、normalizeText で "\n" に変換します。
+	lineBreakMarker = "\u2028"
+
+	// contextCheckInterval は、本文の収集中に context のキャンセルを確認する要素数の間隔です。
+	contextCheckInterval = 256
+
+	// DefaultTitlePrefix と DefaultTableCaptionPrefix は、タイトルと表題の行に付ける既定の接頭辞です。
+	// WithTitlePrefix と WithTableCaptionPrefix で変更できます。
+	DefaultTitlePrefix        = "【記事タイトル】 "
+	DefaultTableCaptionPrefix = "【表題】 "
 )
 
+// ErrNoContent は、ページからタイトルも本文も抽出できなかった場合にテキスト抽出系のメソッドと
+// FetchAndExtract が返すエラーです。ports.ErrNoContent と同一の値のため、
+// スクレイパーが返す本文未検出のエラーも errors.Is(err, extract.ErrNoContent) で判定できます。
+var ErrNoContent = ports.ErrNoContent
+
 // ----------------------------------------------------------------------
 // メイン関数 (メソッド化)
 // ----------------------------------------------------------------------
 
+// FetchAndExtractTextWith は、呼び出し単位のオプションを Extractor の設定に重ねて FetchAndExtractText を実行します。
+// オプションは Extractor の複製に適用されるため、共有された Extractor の設定は変更されず、
+// 複数のゴルーチンから同時に呼び出しても安全です。
+func (e *Extractor) FetchAndExtractTextWith(ctx context.Context, url string, opts ...Option) (text string, hasBodyFound bool, err error) {
+	return e.withOptions(opts).FetchAndExtractText(ctx, url)
+}
+
+// withOptions は、opts を適用した Extractor の複製を返します。opts が空の場合は e 自身を返します。
+func (e *Extractor) withOptions(opts []Option) *Extractor {
+	if len(opts) == 0 {
+		return e
+	}
+	scoped := *e
+	for _, opt := range opts {
+		opt(&scoped)
+	}
+	scoped.prepareDropPhrases()
+	return &scoped
+}
+
 // FetchAndExtractText は指定されたURLからコンテンツを取得し、整形されたテキストを抽出します。
 func (e *Extractor) FetchAndExtractText(ctx context.Context, url string) (text string, hasBodyFound bool, err error) {
+	parts, hasBodyFound, err := e.FetchAndExtractParts(ctx, url)
+	if err != nil {
+		return "", false, err
+	}
+	return joinParts(parts), hasBodyFound, nil
+}
+
+// FetchAndExtractParts は、FetchAndExtractText が結合する前の各パーツ (タイトル、段落、表、コードブロックなど) を返します。
+// パーツ自体が空行を含む場合 (コードブロックなど) でも、呼び出し側で "\n\n" による分割を行わずに
+// 独自の整形やチャンク分割を行えます。
+func (e *Extractor) FetchAndExtractParts(ctx context.Context, url string) (parts []string, hasBodyFound bool, err error) {
 	// 1. Fetcherから生のバイト配列を取得 (通信の責務)
 	htmlBytes, err := e.fetcher.FetchBytes(ctx, url)
 	if err != nil {
-		return "", false, err
+		return nil, false, err
+	}
+
+	// 2. HTMLを解析し、本文を抽出 (解析の責務)
+	doc, err := e.parseDocument(ctx, bytes.NewReader(htmlBytes), "")
+	if err != nil {
+		return nil, false, err
 	}
 
-	return e.ExtractText(ctx, bytes.NewReader(htmlBytes))
+	var frameURL string
+	if e.followIframes {
+		frameURL = findSameOriginIframe(doc, url)
+	}
+
+	parts, hasBodyFound, err = e.extractContentParts(ctx, doc, url)
+
+	// 3. 本文が得られない場合は同一オリジンの iframe から抽出を試みます
+	if frameURL != "" && (err != nil || !hasBodyFound) && ctx.Err() == nil {
+		if frameDoc, ok := e.fetchIframeDocument(ctx, frameURL); ok {
+			if frameParts, frameHasBody, frameErr := e.extractContentParts(ctx, frameDoc, frameURL); frameErr == nil && frameHasBody {
+				return frameParts, true, nil
+			}
+		}
+	}
+	return parts, hasBodyFound, err
 }
 
 // ExtractText は取得済みのHTMLコンテンツから整形されたテキストを抽出します。
 func (e *Extractor) ExtractText(ctx context.Context, reader io.Reader) (text string, hasBodyFound bool, err error) {
-	if err := ctx.Err(); err != nil {
+	return e.extractTextFromReader(ctx, reader, "", "")
+}
+
+// ExtractFromReader は、キャッシュやローカルファイルなど Fetcher を介さずに用意したHTMLから
+// 整形されたテキストを抽出します。pageURL はリンクや画像の相対URLの解決と frontmatter に使用し、
+// 空文字列の場合は ExtractText と同じ結果になります。
+func (e *Extractor) ExtractFromReader(ctx context.Context, reader io.Reader, pageURL string) (text string, hasBodyFound bool, err error) {
+	return e.extractTextFromReader(ctx, reader, pageURL, "")
+}
+
+// ExtractFromBytes は、取得済みのHTMLのバイト列から ExtractFromReader と同様にテキストを抽出します。
+func (e *Extractor) ExtractFromBytes(ctx context.Context, htmlBytes []byte, pageURL string) (text string, hasBodyFound bool, err error) {
+	return e.ExtractFromReader(ctx, bytes.NewReader(htmlBytes), pageURL)
+}
+
+// ExtractTextWithContentType は、Content-Type ヘッダーで宣言された charset に従って
+// HTMLコンテンツを UTF-8 に変換してから、整形されたテキストを抽出します。
+// WithAllowedContentTypes が設定されている場合は、許可されていない Content-Type に対して
+// ErrUnsupportedContentType を返し、text/plain はHTMLとして解析せずにそのまま返します。
+func (e *Extractor) ExtractTextWithContentType(ctx context.Context, reader io.Reader, contentType string) (text string, hasBodyFound bool, err error) {
+	return e.extractTextFromReader(ctx, reader, "", contentType)
+}
+
+// extractTextFromReader はHTMLを解析し、ページURLを考慮して整形されたテキストを抽出します。
+func (e *Extractor) extractTextFromReader(ctx context.Context, reader io.Reader, pageURL, contentType string) (text string, hasBodyFound bool, err error) {
+	mediaType, err := e.checkContentType(contentType)
+	if err != nil {
+		return "", false, err
+	}
+	if mediaType == "text/plain" {
+		if err := ctx.Err(); err != nil {
+			return "", false, err
+		}
+		return e.extractPlainText(reader, contentType)
+	}
+
+	doc, err := e.parseDocument(ctx, reader, contentType)
+	if err != nil {
 		return "", false, err
 	}
 
-	doc, err := goquery.NewDocumentFromReader(reader)
+	return e.extractContentText(ctx, doc, pageURL)
+}
+
+// parseDocument は文字コードを UTF-8 に変換したうえでHTMLを解析します。
+func (e *Extractor) parseDocument(ctx context.Context, reader io.Reader, contentType string) (*goquery.Document, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	reader, err := e.decodeReader(reader, contentType)
 	if err != nil {
-		return "", false, fmt.Errorf("HTML解析に失敗しました: %w", err)
+		return nil, err
+	}
+	if e.maxHTMLBytes > 0 {
+		reader, err = limitHTML(reader, e.maxHTMLBytes)
+		if err != nil {
+			return nil, fmt.Errorf("HTMLの読み込みに失敗しました: %w", err)
+		}
 	}
 
-	return e.extractContentText(doc)
+	doc, err := goquery.NewDocumentFromReader(reader)
+	if err != nil {
+		return nil, fmt.Errorf("HTML解析に失敗しました: %w", err)
+	}
+	return doc, nil
 }
 
 // extractContentText はgoquery.Documentから本文とタイトルを抽出し、整形します。
-func (e *Extractor) extractContentText(doc *goquery.Document) (text string, hasBodyFound bool, err error) {
-	var parts []string
+func (e *Extractor) extractContentText(ctx context.Context, doc *goquery.Document, pageURL string) (text string, hasBodyFound bool, err error) {
+	parts, hasBodyFound, err := e.extractContentParts(ctx, doc, pageURL)
+	if err != nil {
+		return "", false, err
+	}
+	return joinParts(parts), hasBodyFound, nil
+}
+
+// extractContentParts はgoquery.Documentから本文とタイトルを抽出し、出力順に並べたパーツを返します。
+func (e *Extractor) extractContentParts(ctx context.Context, doc *goquery.Document, pageURL string) (parts []string, hasBodyFound bool, err error) {
+	var description string
+	if e.descriptionFallback {
+		description = findDescription(doc)
+	}
+	var frontmatter string
+	if e.frontmatter && e.outputFormat == FormatMarkdown {
+		frontmatter = renderFrontmatter(e.readFrontmatterFields(doc, pageURL))
+	}
+
+	collected, _, err := e.collectPartsWithFallback(ctx, doc, pageURL)
+	if err != nil {
+		return nil, false, err
+	}
+	title, bodyParts := collected.title, collected.body
+	if e.detectSoft404 && e.isSoft404(title, bodyParts) {
+		return nil, false, ErrSoftNotFound
+	}
+
+	hasTitle := title != ""
+	if hasTitle {
+		parts = append(parts, e.titlePrefix+title)
+	}
+	parts = append(parts, bodyParts...)
+
+	if len(bodyParts) == 0 && description != "" {
+		// 本文が無い場合は概要文で補完します (本文扱いにはしません)
+		parts, hasBodyFound = append(parts, description), false
+	} else {
+		// 抽出結果の検証
+		hasBodyFound, err = validateParts(hasTitle, len(bodyParts) > 0)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	if e.allowTitleOnly && hasTitle {
+		hasBodyFound = true
+	}
+
+	if frontmatter != "" {
+		parts = append([]string{frontmatter}, parts...)
+	}
+	return parts, hasBodyFound, nil
+}
+
+// collectedParts は collectParts がドキュメントから収集した内容です。
+type collectedParts struct {
+	title      string
+	body       []string // 出現順に並べた本文のパーツ (表やコードブロックを含みます)
+	tables     []string // 整形済みの表
+	codeBlocks []string // コードブロックの内容 (フェンスを含みません)
+	codeParts  []int    // body のうちコードブロックのパーツの添字
+	paragraphs []string // 段落 (p, blockquote) のパーツ
+}
+
+// collectPartsWithFallback は collectParts を実行し、本文が得られず WithFallbackRelaxed が
+// 有効な場合は、長さの閾値を最小にした2回目の収集を行います。
+// relaxed は2回目の収集で本文が得られた場合に true になります。
+func (e *Extractor) collectPartsWithFallback(ctx context.Context, doc *goquery.Document, pageURL string) (collected collectedParts, relaxed bool, err error) {
+	collected, err = e.collectParts(ctx, doc, pageURL)
+	if err != nil || len(collected.body) > 0 || !e.fallbackRelaxed || e.relaxedThresholds {
+		return collected, false, err
+	}
+
+	// 共有された Extractor を変更しないよう、複製に緩和設定を適用します
+	lenient := *e
+	lenient.relaxedThresholds = true
+	relaxedParts, err := lenient.collectParts(ctx, doc, pageURL)
+	if err != nil {
+		return collectedParts{}, false, err
+	}
+	if len(relaxedParts.body) > 0 {
+		relaxedParts.title = collected.title
+		return relaxedParts, true, nil
+	}
+	return collected, false, nil
+}
+
+// collectParts はgoquery.Documentからページタイトルと本文の各パーツを収集します。
+// pageURL は Markdown 形式の出力でリンクを絶対URLに解決する際の基準です。
+// 巨大なページでも全体のタイムアウトが効くよう、contextCheckInterval 個の要素ごとに ctx を確認し、
+// キャンセルされていればその時点で収集を打ち切って ctx.Err() を返します。
+func (e *Extractor) collectParts(ctx context.Context, doc *goquery.Document, pageURL string) (collected collectedParts, err error) {
 	// 1. ページタイトルを抽出
-	pageTitle := strings.TrimSpace(doc.Find("title").First().Text())
-	if pageTitle != "" {
-		parts = append(parts, titlePrefix+pageTitle)
+	title := e.findTitle(doc)
+	if e.normalizeUnicode {
+		title = norm.NFKC.String(title)
+	}
+	if e.emojiHandling != EmojiKeep {
+		title = text.NormalizeText(e.applyEmojiHandling(title))
+	}
+	collected.title = title
+
+	// base 要素は head にあるため、メインコンテンツの特定より前に解決します
+	var base *url.URL
+	if e.outputFormat == FormatMarkdown {
+		base = documentBaseURL(doc, pageURL)
 	}
 
 	// 2. メインコンテンツの特定
 	mainContent := e.findMainContent(doc)
 
 	// 3. ノイズ要素の除去
-	mainContent.Find(noiseSelectors).Remove()
+	mainContent.Find(e.noiseSelector()).Remove()
 
 	// 4. すべての関連コンテンツ要素（p, h*, li, blockquote, table, pre）を結合したセレクター
 	//    このセレクターは、goqueryによってDOMの出現順に走査されます。
@@ -95,125 +389,384 @@ func (e *Extractor) extractContentText(doc *goquery.Document) (text string, hasB
 	//    goqueryは重複を排除し、DOMの深さ優先探索順序で要素を返します。
 	contentSelectors := textExtractionTags + ", table, pre"
 
-	mainContent.Find(contentSelectors).Each(func(i int, s *goquery.Selection) {
-		var content string
+	paragraphs := 0
+	mainContent.Find(contentSelectors).EachWithBreak(func(i int, s *goquery.Selection) bool {
+		if i%contextCheckInterval == 0 {
+			if err = ctx.Err(); err != nil {
+				return false
+			}
+		}
+
+		var content, codeBlock string
+		tagName := goquery.NodeName(s)
 
-		if s.Is("table") {
+		switch tagName {
+		case "table":
 			// テーブルの処理
-			content = processTable(s)
-		} else if s.Is("pre") {
+			content = e.processTable(s)
+		case "pre":
 			// pre タグ (コードブロック) の処理
-			preText := strings.TrimSpace(s.Text())
-			if preText != "" {
-				content = "```\n" + preText + "\n```"
+			codeBlock = e.codeBlockText(s.Text())
+			if codeBlock != "" {
+				content = "```" + codeLanguage(s) + "\n" + codeBlock + "\n```"
 			}
-		} else {
+		default:
 			// 一般的なテキスト要素 (p, h*, li, blockquote) の処理
-			content = e.processGeneralElement(s)
+			content = e.processGeneralElement(s, base)
 		}
 
-		if content != "" {
-			parts = append(parts, content)
+		if content == "" {
+			return true
 		}
+		if e.maxParagraphs > 0 && isParagraphTag(tagName) {
+			if paragraphs >= e.maxParagraphs {
+				// 上限到達後は段落のみを読み飛ばし、設定に応じて収集自体を終了します
+				return !e.stopAtMaxParagraphs
+			}
+			paragraphs++
+		}
+
+		collected.body = append(collected.body, content)
+		switch tagName {
+		case "table":
+			collected.tables = append(collected.tables, content)
+		case "pre":
+			collected.codeBlocks = append(collected.codeBlocks, codeBlock)
+			collected.codeParts = append(collected.codeParts, len(collected.body)-1)
+		default:
+			if isParagraphTag(tagName) {
+				collected.paragraphs = append(collected.paragraphs, content)
+			}
+		}
+		return !(e.stopAtMaxParagraphs && e.maxParagraphs > 0 && paragraphs >= e.maxParagraphs)
 	})
+	if err != nil {
+		return collectedParts{}, err
+	}
 
-	// 5. 抽出結果の検証
-	return e.validateAndFormatResult(parts)
+	return collected, nil
+}
+
+// codeBlockText は pre 要素のテキストをコードブロック用に整えます。
+// WithPreserveCodeIndentation が有効な場合は前後の空行のみを取り除き、各行のインデントを保持します。
+func (e *Extractor) codeBlockText(raw string) string {
+	if !e.preserveCodeIndent {
+		return strings.TrimSpace(raw)
+	}
+
+	lines := strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n")
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// codeLanguagePrefixes は、highlight.js や Prism がコードの言語を示す class 名の接頭辞です。
+var codeLanguagePrefixes = []string{"language-", "lang-"}
+
+// codeLanguage は、pre 要素内の code 要素、または pre 要素自身の class 属性から
+// "language-go" や "lang-python" のように宣言されたコードの言語を返します。
+// 宣言が無い場合は空文字列を返します。
+func codeLanguage(pre *goquery.Selection) string {
+	for _, s := range []*goquery.Selection{pre.ChildrenFiltered("code").First(), pre} {
+		for _, class := range strings.Fields(s.AttrOr("class", "")) {
+			for _, prefix := range codeLanguagePrefixes {
+				if lang, ok := strings.CutPrefix(class, prefix); ok && lang != "" {
+					return lang
+				}
+			}
+		}
+	}
+	return ""
 }
 
 // findMainContent はメインコンテントを取得
+// 特定方法は WithContentDetection の設定に従います。
 func (e *Extractor) findMainContent(doc *goquery.Document) *goquery.Selection {
-	mainContent := doc.Find(mainContentSelectors).First()
+	if e.contentDetection == ContentDetectionScoring {
+		if scored := findMainContentByScore(doc); scored != nil {
+			return scored
+		}
+	}
+
+	mainContent := doc.Find(e.mainContentSelector()).First()
 	if mainContent.Length() == 0 {
+		if e.contentDetection == ContentDetectionFallback {
+			if scored := findMainContentByScore(doc); scored != nil {
+				return scored
+			}
+		}
 		mainContent = doc.Selection.
 			Not("header, footer, nav, aside, .sidebar, script, style, form")
 	}
 	return mainContent
 }
 
-// processGeneralElement は一般的なテキスト要素からテキストを抽出し、整形します。
-// 子孫の pre や table 要素のテキストを含めないようにカスタム走査を行います
-func (e *Extractor) processGeneralElement(s *goquery.Selection) string {
-	var builder strings.Builder
+// mainContentSelector はメインコンテンツの特定に使用するセレクターを返します。
+func (e *Extractor) mainContentSelector() string {
+	if e.mainContentSelectors != "" {
+		return e.mainContentSelectors
+	}
+	return defaultMainContentSelectors
+}
 
-	// s の子孫から pre, table を除外してテキストを抽出する再帰ヘルパー関数
-	var extractText func(sel *goquery.Selection)
-	extractText = func(sel *goquery.Selection) {
-		sel.Contents().Each(func(i int, child *goquery.Selection) {
-			node := child.Get(0) // *html.Node を取得
+// noiseSelector はメインコンテンツから除去するノイズ要素のセレクターを返します。
+func (e *Extractor) noiseSelector() string {
+	if e.noiseSelectors != "" {
+		return e.noiseSelectors
+	}
+	return defaultNoiseSelectors
+}
 
-			if node == nil {
-				return
-			}
+// processGeneralElement は一般的なテキスト要素からテキストを抽出し、整形します。
+// 子孫の pre や table 要素のテキストを含めないようにカスタム走査を行います
+// Markdown 形式では子孫のリンクを base に対して解決した [text](url) として出力します。
+func (e *Extractor) processGeneralElement(s *goquery.Selection, base *url.URL) string {
+	// 要素ごとにセレクターをコンパイルしないよう、タグ名で判定します
+	tagName := goquery.NodeName(s)
+	isHeading := isHeadingTag(tagName)
+	isListItem := tagName == "li"
+	// 入れ子のリストの項目は字下げした別の項目として出力されるため、親の項目のテキストに含めません
+	nestedLists := isListItem
 
-			// テキストノードの場合
-			if node.Type == html.TextNode {
-				// テキストノードの内容は node.Data に格納されている
-				builder.WriteString(node.Data)
-			} else if node.Type == html.ElementNode {
-				// 要素ノードの場合
-				if child.Is("pre") || child.Is("table") {
-					// pre または table 要素はスキップ
-					return
-				}
-				// それ以外の要素ノードは再帰的に処理
-				extractText(child)
-			}
-			// コメントノードやDOCTYPEなどは無視
-		})
+	var content string
+	if node := s.Get(0); node != nil && !needsTextWalk(node, nestedLists) {
+		// 大半の要素は pre や table、br を含まないため、カスタム走査を省略して一括で取得します
+		content = s.Text()
+	} else {
+		content = textExcludingSeparateParts(s.Get(0), nestedLists)
 	}
 
-	// s 自身の子孫を走査してテキストを抽出
-	extractText(s)
-	content := builder.String()
-
-	// 以降は既存のロジックを維持
-	content = text.NormalizeText(content)
-	isHeading := s.Is("h1, h2, h3, h4, h5, h6")
-	isListItem := s.Is("li")
-	if content == "" {
+	// 長さ判定の前に正規化します (全角英数字は NFKC で半角として数えます)
+	content = e.normalizeText(content)
+	if content == "" || e.containsDropPhrase(content) {
+		return ""
+	}
+	// 関連記事やタグクラウドなど、リンクが大半を占める段落やリスト項目はナビゲーションとみなします
+	if (tagName == "p" || isListItem) && e.exceedsLinkDensity(s.Get(0)) {
 		return ""
 	}
 	if isHeading {
-		if len(content) > MinHeadingLength {
-			return "## " + content
+		if utf8.RuneCountInString(content) > e.headingMinLength(tagName) {
+			return e.headingPrefix(tagName) + e.withInlineMarkdown(s, content, base)
 		}
 	} else {
-		if isListItem || len(content) > MinParagraphLength {
-			return content
+		if isListItem {
+			return e.listItemPrefix(s.Get(0)) + e.withInlineMarkdown(s, content, base)
+		}
+		if utf8.RuneCountInString(content) > e.minParagraphLength() {
+			return e.withInlineMarkdown(s, content, base)
+		}
+		// 短い行が連続する構造 (チャットログや書き起こしなど) では短い段落も保持します
+		if e.keepShortLines && e.inShortLineRun(s) {
+			return e.withInlineMarkdown(s, content, base)
 		}
 	}
 	return ""
 }
 
-// processTable は goquery.Selection からテーブルの内容を抽出し、整形します。
-func processTable(s *goquery.Selection) string { // パッケージレベル関数に
-	var tableContent []string
-	captionText := strings.TrimSpace(s.Find("caption").First().Text())
-	if captionText != "" {
-		tableContent = append(tableContent, tableCaptionPrefix+captionText)
+// exceedsLinkDensity は、node のリンク密度が WithMaxLinkDensity の上限を超えるかを判定します。
+func (e *Extractor) exceedsLinkDensity(node *html.Node) bool {
+	if node == nil || !hasLinkDescendant(node) {
+		return false
 	}
-	s.Find("tr").Each(func(rowIndex int, row *goquery.Selection) {
-		var rowTexts []string
-		row.Find("th, td").Each(func(cellIndex int, cell *goquery.Selection) {
-			rowTexts = append(rowTexts, text.NormalizeText(cell.Text()))
-		})
-		tableContent = append(tableContent, strings.Join(rowTexts, " | "))
-	})
-	if len(tableContent) > 0 {
-		return strings.Join(tableContent, "\n")
+	return linkDensity(node) > e.maxLinkDensity
+}
+
+// withInlineMarkdown は、Markdown 形式の出力で s がリンクや強調を含む場合に、
+// それらを Markdown のインライン記法に置き換えたテキストを返します。それ以外の場合は content をそのまま返します。
+// 長さの判定にURLや記号を含めないよう、採用が決まった要素に対してのみ呼び出します。
+func (e *Extractor) withInlineMarkdown(s *goquery.Selection, content string, base *url.URL) string {
+	node := s.Get(0)
+	if e.outputFormat != FormatMarkdown || node == nil || !hasInlineMarkdownDescendant(node) {
+		return content
 	}
-	return ""
+	return e.normalizeText(markdownInlineText(node, base, goquery.NodeName(s) == "li"))
 }
 
-// validateAndFormatResult はフォーマットを確認
-func (e *Extractor) validateAndFormatResult(parts []string) (text string, hasBodyFound bool, err error) {
-	if len(parts) == 0 {
-		return "", false, fmt.Errorf("webページから何も抽出できませんでした")
+// isSeparatePart は、child が別のパーツとして出力されるため親要素のテキストに含めない要素かを判定します。
+// pre と table 要素は常に対象で、nestedLists が true の場合は入れ子のリスト (ul, ol) も対象です。
+func isSeparatePart(child *html.Node, nestedLists bool) bool {
+	switch child.Data {
+	case "pre", "table":
+		return true
+	case "ul", "ol":
+		return nestedLists
 	}
-	isTitleOnly := len(parts) == 1 && strings.HasPrefix(parts[0], titlePrefix)
-	if isTitleOnly {
-		return parts[0], false, nil
+	return false
+}
+
+// needsTextWalk は、node の子孫に isSeparatePart に該当する要素または br 要素が含まれ、
+// テキストの取得にカスタム走査が必要かを判定します。
+// セレクターを使わずにノードを直接走査するため、要素ごとに呼び出しても低コストです。
+func needsTextWalk(node *html.Node, nestedLists bool) bool {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode {
+			continue
+		}
+		if child.Data == "br" || isSeparatePart(child, nestedLists) || needsTextWalk(child, nestedLists) {
+			return true
+		}
 	}
-	return strings.Join(parts, "\n\n"), true, nil
+	return false
+}
+
+// textExcludingSeparateParts は、子孫の pre や table 要素 (nestedLists が true の場合は入れ子のリストも) を
+// 除いた node のテキストを返します。
+func textExcludingSeparateParts(node *html.Node, nestedLists bool) string {
+	var builder strings.Builder
+	writeTextExcludingSeparateParts(&builder, node, nestedLists)
+	return builder.String()
+}
+
+// writeTextExcludingSeparateParts は node の子孫のテキストノードを出現順に書き込みます。
+// pre と table 要素は別のパーツとして出力されるため、その内容はスキップします。
+// br 要素は lineBreakMarker として書き込み、normalizeText で改行に変換します。
+// goquery.Selection を生成せずにノードを直接走査するため、アロケーションを抑えられます。
+func writeTextExcludingSeparateParts(builder *strings.Builder, node *html.Node, nestedLists bool) {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		switch child.Type {
+		case html.TextNode:
+			builder.WriteString(child.Data)
+		case html.ElementNode:
+			if child.Data == "br" {
+				builder.WriteString(lineBreakMarker)
+				continue
+			}
+			if isSeparatePart(child, nestedLists) {
+				continue
+			}
+			writeTextExcludingSeparateParts(builder, child, nestedLists)
+		}
+		// コメントノードやDOCTYPEなどは無視
+	}
+}
+
+// isParagraphTag は、タグ名が WithMaxParagraphs で数える段落 (p, blockquote) であるかを判定します。
+func isParagraphTag(tagName string) bool {
+	return tagName == "p" || tagName == "blockquote"
+}
+
+// isHeadingTag はタグ名が h1〜h6 のいずれかであるかを判定します。
+func isHeadingTag(tagName string) bool {
+	return len(tagName) == 2 && tagName[0] == 'h' && tagName[1] >= '1' && tagName[1] <= '6'
+}
+
+// inShortLineRun は、s が同じタグの短いテキスト要素が shortLineRunLength 個以上
+// 連続する並びの一部であるかを判定します。兄弟要素も段落と同じ正規化と最小文字数で判定します。
+func (e *Extractor) inShortLineRun(s *goquery.Selection) bool {
+	name := goquery.NodeName(s)
+	isShortSibling := func(sib *goquery.Selection) bool {
+		if sib.Length() == 0 || goquery.NodeName(sib) != name {
+			return false
+		}
+		content := e.normalizeText(sib.Text())
+		return content != "" && utf8.RuneCountInString(content) <= e.minParagraphLength()
+	}
+
+	run := 1
+	for sib := s.Prev(); run < shortLineRunLength && isShortSibling(sib); sib = sib.Prev() {
+		run++
+	}
+	for sib := s.Next(); run < shortLineRunLength && isShortSibling(sib); sib = sib.Next() {
+		run++
+	}
+	return run >= shortLineRunLength
+}
+
+// minParagraphLength は段落を本文として採用する最小文字数を返します。
+func (e *Extractor) minParagraphLength() int {
+	if e.relaxedThresholds {
+		return 0
+	}
+	return MinParagraphLength
+}
+
+// headingMinLength は見出しタグ名 (h1〜h6) に対応する最小文字数を返します。
+func (e *Extractor) headingMinLength(tagName string) int {
+	if e.relaxedThresholds {
+		return 0
+	}
+	level, err := strconv.Atoi(strings.TrimPrefix(tagName, "h"))
+	if err == nil {
+		if n, ok := e.headingMinLengths[level]; ok {
+			return n
+		}
+	}
+	return MinHeadingLength
+}
+
+// normalizeText は連続する空白を正規化し、設定に応じて Unicode 正規化 (NFKC) と絵文字の処理を適用します。
+// br 要素の位置 (lineBreakMarker) の改行は保持し、連続する場合は1つにまとめます。
+func (e *Extractor) normalizeText(s string) string {
+	if strings.Contains(s, lineBreakMarker) {
+		// br による改行は保持し、行ごとに空白を正規化します。空になった行は取り除きます
+		lines := strings.Split(s, lineBreakMarker)
+		kept := lines[:0]
+		for _, line := range lines {
+			if line = e.normalizeLine(line); line != "" {
+				kept = append(kept, line)
+			}
+		}
+		return strings.Join(kept, "\n")
+	}
+	return e.normalizeLine(s)
+}
+
+// normalizeLine は1行分のテキストに Unicode 正規化、残った文字参照のデコード、不可視の文字の除去と絵文字の処理を適用し、
+// 改行を含む連続する空白を1つの空白にまとめます。
+func (e *Extractor) normalizeLine(s string) string {
+	if e.normalizeUnicode {
+		s = norm.NFKC.String(s)
+	}
+	s = stripInvisibleChars(decodeRemainingEntities(s))
+	// 絵文字の除去で生じた連続する空白も、続く空白の正規化でまとめられます
+	s = e.applyEmojiHandling(s)
+	return text.NormalizeText(s)
+}
+
+// decodeRemainingEntities は、goquery によるデコード後も残る文字参照 (二重にエスケープされた
+// "&amp;nbsp;" に由来する "&nbsp;" など) をデコードします。
+func decodeRemainingEntities(s string) string {
+	if !strings.Contains(s, "&") {
+		return s
+	}
+	return html.UnescapeString(s)
+}
+
+// stripInvisibleChars は、ゼロ幅スペースを空白に置き換え、ソフトハイフンや BOM などの
+// 不可視の文字と制御文字を取り除きます。絵文字のシーケンスや一部の文字体系で意味を持つ
+// ゼロ幅接合子 (U+200D) と非接合子 (U+200C) は保持します。
+func stripInvisibleChars(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '\u200b': // ゼロ幅スペースは単語の区切りとして扱います
+			return ' '
+		case '\u00ad', '\u2060', '\ufeff':
+			return -1
+		}
+		if unicode.IsControl(r) && !unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// validateParts は、タイトルまたは本文のいずれかが抽出できたことを確認し、本文を含むかを返します。
+// タイトルのみの場合は本文なしとして扱います。
+// 本文の段落がタイトルの接頭辞と同じ文字列で始まる場合や、接頭辞が空の場合にも誤判定しないよう、
+// パーツの文字列ではなく抽出時に記録したフラグで判定します。
+func validateParts(hasTitle, hasBody bool) (hasBodyFound bool, err error) {
+	if !hasTitle && !hasBody {
+		return false, ErrNoContent
+	}
+	return hasBody, nil
+}
+
+// joinParts はパーツを空行で区切って1つのテキストに結合します。
+func joinParts(parts []string) string {
+	return strings.Join(parts, "\n\n")
 }
@@ -4,13 +4,22 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/shouni/go-web-exact/v2/extract"
+	"github.com/shouni/go-web-exact/v2/ports"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/encoding/japanese"
 )
 
+// Extractor は ScrapeRunner が Content-Type を渡せるよう ports.ContentTypeExtractor を実装します
+var _ ports.ContentTypeExtractor = (*extract.Extractor)(nil)
+
 // ======================================================================
 // モック (Mock) の定義
 // ======================================================================
@@ -198,3 +207,2437 @@ func TestExtractText(t *testing.T) {
 	assert.True(t, actualBodyFound)
 	assert.Equal(t, titlePrefix+"Reader Title"+"\n\n"+body, actualText)
 }
+
+func TestFetchAndExtract(t *testing.T) {
+	body := "This paragraph is long enough to be treated as extracted article body."
+
+	t.Run("structured_result", func(t *testing.T) {
+		fetcher := &MockFetcher{
+			htmlContent: fmt.Sprintf(`<html><head><title>Structured</title></head><body><main><p>%s</p></main></body></html>`, body),
+		}
+		extractor, err := extract.NewExtractor(fetcher)
+		assert.NoError(t, err)
+
+		result, err := extractor.FetchAndExtract(context.Background(), "https://example.com/articles/1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Structured", result.Title)
+		assert.Equal(t, body, result.Body)
+		assert.True(t, result.HasBody)
+	})
+
+	t.Run("empty_document_error", func(t *testing.T) {
+		fetcher := &MockFetcher{htmlContent: `<html><head></head><body></body></html>`}
+		extractor, err := extract.NewExtractor(fetcher)
+		assert.NoError(t, err)
+
+		result, err := extractor.FetchAndExtract(context.Background(), "https://example.com/empty")
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestFetchAndExtract_Favicon(t *testing.T) {
+	testCases := []struct {
+		name     string
+		head     string
+		expected string
+	}{
+		{
+			name:     "relative_icon_is_resolved",
+			head:     `<link rel="icon" href="/static/icon.png">`,
+			expected: "https://example.com/static/icon.png",
+		},
+		{
+			name: "largest_declared_size_is_preferred",
+			head: `<link rel="icon" sizes="16x16" href="/icon-16.png">
+			       <link rel="apple-touch-icon" sizes="180x180" href="/icon-180.png">
+			       <link rel="icon" sizes="32x32" href="/icon-32.png">`,
+			expected: "https://example.com/icon-180.png",
+		},
+		{
+			name:     "shortcut_icon_with_absolute_url",
+			head:     `<link rel="shortcut icon" href="https://cdn.example.net/favicon.ico">`,
+			expected: "https://cdn.example.net/favicon.ico",
+		},
+		{
+			name:     "fallback_to_host_root",
+			head:     `<link rel="stylesheet" href="/style.css">`,
+			expected: "https://example.com/favicon.ico",
+		},
+		{
+			name:     "base_href_is_honored",
+			head:     `<base href="https://static.example.com/assets/"><link rel="icon" href="icon.png">`,
+			expected: "https://static.example.com/assets/icon.png",
+		},
+		{
+			name:     "relative_base_href",
+			head:     `<base href="/v2/"><link rel="icon" href="icon.png">`,
+			expected: "https://example.com/v2/icon.png",
+		},
+		{
+			name:     "protocol_relative_icon",
+			head:     `<link rel="icon" href="//cdn.example.com/icon.png">`,
+			expected: "https://cdn.example.com/icon.png",
+		},
+		{
+			name:     "fallback_ignores_base_href",
+			head:     `<base href="https://static.example.com/assets/">`,
+			expected: "https://example.com/favicon.ico",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fetcher := &MockFetcher{
+				htmlContent: `<html><head><title>Icon</title>` + tc.head + `</head><body></body></html>`,
+			}
+			extractor, err := extract.NewExtractor(fetcher)
+			assert.NoError(t, err)
+
+			result, err := extractor.FetchAndExtract(context.Background(), "https://example.com/blog/post")
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, result.Favicon)
+		})
+	}
+}
+
+func TestFetchAndExtract_InlineJSON(t *testing.T) {
+	html := `<html><head><title>SPA</title></head><body><div id="root"></div>
+		<script id="__NEXT_DATA__" type="application/json">{"props":{"title":"Next"}}</script>
+		<script>window.__INITIAL_STATE__ = {"items":[{"name":"a}b"}]};console.log("x");</script>
+		<script type="application/json">{broken</script>
+	</body></html>`
+
+	t.Run("enabled", func(t *testing.T) {
+		extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html}, extract.WithExtractInlineJSON(true))
+		assert.NoError(t, err)
+
+		result, err := extractor.FetchAndExtract(context.Background(), "https://example.com/spa")
+
+		assert.NoError(t, err)
+		assert.False(t, result.HasBody)
+		assert.Equal(t, []string{
+			`{"props":{"title":"Next"}}`,
+			`{"items":[{"name":"a}b"}]}`,
+		}, result.InlineJSON)
+	})
+
+	t.Run("disabled_by_default", func(t *testing.T) {
+		extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html})
+		assert.NoError(t, err)
+
+		result, err := extractor.FetchAndExtract(context.Background(), "https://example.com/spa")
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.InlineJSON)
+	})
+}
+
+func TestFetchAndExtractText_DescriptionFallback(t *testing.T) {
+	const titlePrefix = "【記事タイトル】 "
+	html := `<html><head><title>Landing</title>
+		<meta name="description" content="Plain description">
+		<meta property="og:description" content="OG description">
+	</head><body><p>Short text</p></body></html>`
+
+	t.Run("enabled", func(t *testing.T) {
+		extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html}, extract.WithDescriptionFallback(true))
+		assert.NoError(t, err)
+
+		text, hasBody, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/")
+
+		assert.NoError(t, err)
+		assert.False(t, hasBody, "概要文で補完しても本文扱いにはならないべき")
+		assert.Equal(t, titlePrefix+"Landing\n\nOG description", text)
+	})
+
+	t.Run("disabled_by_default", func(t *testing.T) {
+		extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html})
+		assert.NoError(t, err)
+
+		text, hasBody, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/")
+
+		assert.NoError(t, err)
+		assert.False(t, hasBody)
+		assert.Equal(t, titlePrefix+"Landing", text)
+	})
+}
+
+func TestFetchAndExtractText_HeadingMinLengths(t *testing.T) {
+	const titlePrefix = "【記事タイトル】 "
+	longParagraph := "This is a long paragraph with more than twenty characters and it should be extracted as body content."
+	html := fmt.Sprintf(`<html><head><title>Levels</title></head><body><article>
+		<h1>Go</h1>
+		<h5>Minor note</h5>
+		<p>%s</p>
+	</article></body></html>`, longParagraph)
+
+	extractor, err := extract.NewExtractor(
+		&MockFetcher{htmlContent: html},
+		extract.WithHeadingMinLengths(map[int]int{1: 0, 5: 20}),
+	)
+	assert.NoError(t, err)
+
+	text, hasBody, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/levels")
+
+	assert.NoError(t, err)
+	assert.True(t, hasBody)
+	assert.Equal(t, titlePrefix+"Levels\n\n## Go\n\n"+longParagraph, text)
+}
+
+func TestFetchAndExtractText_Markdown(t *testing.T) {
+	const titlePrefix = "【記事タイトル】 "
+	longParagraph := "This is a long paragraph with more than twenty characters and it should be extracted as body content."
+	html := fmt.Sprintf(`<html lang="ja"><head><title>Doc "Title"</title>
+		<meta name="author" content="Taro">
+		<meta property="article:published_time" content="2024-05-01T09:00:00+09:00">
+	</head><body><article>
+		<h1>Top Level Heading</h1>
+		<h3>Third Level Heading</h3>
+		<p>%s</p>
+	</article></body></html>`, longParagraph)
+
+	t.Run("heading_levels_are_preserved", func(t *testing.T) {
+		extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html}, extract.WithOutputFormat(extract.FormatMarkdown))
+		assert.NoError(t, err)
+
+		text, _, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/doc")
+
+		assert.NoError(t, err)
+		assert.Equal(t, titlePrefix+`Doc "Title"`+"\n\n# Top Level Heading\n\n### Third Level Heading\n\n"+longParagraph, text)
+	})
+
+	t.Run("frontmatter", func(t *testing.T) {
+		extractor, err := extract.NewExtractor(
+			&MockFetcher{htmlContent: html},
+			extract.WithOutputFormat(extract.FormatMarkdown),
+			extract.WithFrontmatter(true),
+		)
+		assert.NoError(t, err)
+
+		text, hasBody, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/doc")
+
+		assert.NoError(t, err)
+		assert.True(t, hasBody)
+		assert.True(t, strings.HasPrefix(text, "---\n"+
+			`title: "Doc \"Title\""`+"\n"+
+			`url: "https://example.com/doc"`+"\n"+
+			`author: "Taro"`+"\n"+
+			`published: "2024-05-01T09:00:00+09:00"`+"\n"+
+			`language: "ja"`+"\n"+
+			"---\n\n"+titlePrefix), text)
+	})
+
+	t.Run("frontmatter_reads_jsonld_author_and_date", func(t *testing.T) {
+		extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: fmt.Sprintf(`<html><head><title>JSON-LD</title>
+			<script type="application/ld+json">{"@type": "Article", "author": {"@type": "Person", "name": "JL"}, "datePublished": "2024-01-02"}</script>
+		</head><body><article><p>%s</p></article></body></html>`, longParagraph)},
+			extract.WithOutputFormat(extract.FormatMarkdown),
+			extract.WithFrontmatter(true),
+		)
+		assert.NoError(t, err)
+
+		text, _, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/jsonld")
+
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(text, "---\n"+
+			`title: "JSON-LD"`+"\n"+
+			`url: "https://example.com/jsonld"`+"\n"+
+			`author: "JL"`+"\n"+
+			`published: "2024-01-02T00:00:00Z"`+"\n"+
+			"---\n\n"), text)
+	})
+
+	t.Run("frontmatter_is_ignored_for_plain_text", func(t *testing.T) {
+		extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html}, extract.WithFrontmatter(true))
+		assert.NoError(t, err)
+
+		text, _, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/doc")
+
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(text, titlePrefix), text)
+	})
+}
+
+func TestFetchAndExtractText_MarkdownInlineLinks(t *testing.T) {
+	const titlePrefix = "【記事タイトル】 "
+	html := `<html><head><title>Links</title></head><body><article>
+		<p>Please <a href="/docs/setup">see the <strong>setup</strong> docs</a> before installing the package.</p>
+		<p>Read <a href="//cdn.example.com/spec (v2).pdf">the [draft] spec</a> or <a href="javascript:void(0)">open the menu</a> first, before starting on the integration work.</p>
+	</article></body></html>`
+
+	t.Run("markdown", func(t *testing.T) {
+		extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html}, extract.WithOutputFormat(extract.FormatMarkdown))
+		assert.NoError(t, err)
+
+		text, hasBody, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/guide/index.html")
+
+		assert.NoError(t, err)
+		assert.True(t, hasBody)
+		assert.Equal(t, titlePrefix+"Links\n\n"+
+			"Please [see the **setup** docs](https://example.com/docs/setup) before installing the package.\n\n"+
+			`Read [the \[draft\] spec](https://cdn.example.com/spec%20%28v2%29.pdf) or open the menu first, before starting on the integration work.`, text)
+	})
+
+	t.Run("plain_text", func(t *testing.T) {
+		extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html})
+		assert.NoError(t, err)
+
+		text, _, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/guide/index.html")
+
+		assert.NoError(t, err)
+		assert.Equal(t, titlePrefix+"Links\n\n"+
+			"Please see the setup docs before installing the package.\n\n"+
+			"Read the [draft] spec or open the menu first, before starting on the integration work.", text)
+	})
+}
+
+// closableFetcher は io.Closer を実装した Fetcher のモックです。
+type closableFetcher struct {
+	MockFetcher
+	closed bool
+}
+
+func (f *closableFetcher) Close() error {
+	f.closed = true
+	return nil
+}
+
+func TestExtractor_Close(t *testing.T) {
+	fetcher := &closableFetcher{}
+	extractor, err := extract.NewExtractor(fetcher)
+	assert.NoError(t, err)
+
+	assert.NoError(t, extractor.Close())
+	assert.True(t, fetcher.closed)
+}
+
+func TestExtractTextWithContentType_Charset(t *testing.T) {
+	const titlePrefix = "【記事タイトル】 "
+	body := "これはShift_JISでエンコードされた十分な長さを持つ本文の段落です。"
+	html := fmt.Sprintf(`<html><head><title>日本語タイトル</title></head><body><main><p>%s</p></main></body></html>`, body)
+	sjis, err := japanese.ShiftJIS.NewEncoder().String(html)
+	assert.NoError(t, err)
+	expected := titlePrefix + "日本語タイトル\n\n" + body
+
+	t.Run("charset_from_content_type", func(t *testing.T) {
+		extractor, err := extract.NewExtractor(&MockFetcher{})
+		assert.NoError(t, err)
+
+		text, hasBody, err := extractor.ExtractTextWithContentType(context.Background(), strings.NewReader(sjis), "text/html; charset=Shift_JIS")
+
+		assert.NoError(t, err)
+		assert.True(t, hasBody)
+		assert.Equal(t, expected, text)
+	})
+
+	t.Run("forced_charset_overrides_header", func(t *testing.T) {
+		extractor, err := extract.NewExtractor(&MockFetcher{}, extract.WithForcedCharset("shift_jis"))
+		assert.NoError(t, err)
+
+		text, _, err := extractor.ExtractTextWithContentType(context.Background(), strings.NewReader(sjis), "text/html; charset=utf-8")
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, text)
+	})
+
+	t.Run("unknown_forced_charset_is_error", func(t *testing.T) {
+		extractor, err := extract.NewExtractor(&MockFetcher{}, extract.WithForcedCharset("no-such-charset"))
+		assert.NoError(t, err)
+
+		_, _, err = extractor.ExtractTextWithContentType(context.Background(), strings.NewReader(sjis), "")
+
+		assert.Error(t, err)
+	})
+}
+
+func TestFetchAndExtractText_MetaCharset(t *testing.T) {
+	const titlePrefix = "【記事タイトル】 "
+
+	t.Run("shift_jis_meta_charset", func(t *testing.T) {
+		sjis, err := os.ReadFile(filepath.Join("testdata", "shift_jis.html"))
+		assert.NoError(t, err)
+		extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: string(sjis)})
+		assert.NoError(t, err)
+
+		text, hasBody, err := extractor.FetchAndExtractText(context.Background(), "https://example.jp/news")
+
+		assert.NoError(t, err)
+		assert.True(t, hasBody)
+		assert.Equal(t, titlePrefix+"日本語ニュース\n\n"+
+			"## 経済ニュースの見出し\n\n"+
+			"これはShift_JISでエンコードされた十分な長さを持つ本文の段落です。\n\n"+
+			"全角記号「」や半角カナｱｲｳを含む二つ目の段落も正しく変換されるのです。", text)
+	})
+
+	t.Run("euc_jp_http_equiv", func(t *testing.T) {
+		body := "これはEUC-JPでエンコードされた十分な長さを持つ本文の段落です。"
+		html := fmt.Sprintf(`<html><head><meta http-equiv="Content-Type" content="text/html; charset=EUC-JP">
+			<title>日本語タイトル</title></head><body><main><p>%s</p></main></body></html>`, body)
+		eucjp, err := japanese.EUCJP.NewEncoder().String(html)
+		assert.NoError(t, err)
+		extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: eucjp})
+		assert.NoError(t, err)
+
+		text, _, err := extractor.FetchAndExtractText(context.Background(), "https://example.jp/news")
+
+		assert.NoError(t, err)
+		assert.Equal(t, titlePrefix+"日本語タイトル\n\n"+body, text)
+	})
+
+	t.Run("content_type_overrides_meta", func(t *testing.T) {
+		body := "これはUTF-8のまま配信された十分な長さを持つ本文の段落です。"
+		html := fmt.Sprintf(`<html><head><meta charset="Shift_JIS"><title>日本語タイトル</title></head><body><main><p>%s</p></main></body></html>`, body)
+		extractor, err := extract.NewExtractor(&MockFetcher{})
+		assert.NoError(t, err)
+
+		text, _, err := extractor.ExtractTextWithContentType(context.Background(), strings.NewReader(html), "text/html; charset=utf-8")
+
+		assert.NoError(t, err)
+		assert.Equal(t, titlePrefix+"日本語タイトル\n\n"+body, text)
+	})
+}
+
+// mapFetcher はURLごとに異なるHTMLを返すテスト用 Fetcher です。
+type mapFetcher struct {
+	pages   map[string]string
+	fetched []string
+}
+
+func (m *mapFetcher) FetchBytes(ctx context.Context, url string) ([]byte, error) {
+	m.fetched = append(m.fetched, url)
+	page, ok := m.pages[url]
+	if !ok {
+		return nil, fmt.Errorf("not found: %s", url)
+	}
+	return []byte(page), nil
+}
+
+func TestFetchAndExtractText_FollowIframes(t *testing.T) {
+	const titlePrefix = "【記事タイトル】 "
+	body := "This article body lives inside a same-origin iframe document."
+	newFetcher := func(frameSrc string) *mapFetcher {
+		return &mapFetcher{pages: map[string]string{
+			"https://example.com/post": `<html><head><title>Wrapper</title></head><body><iframe src="` + frameSrc + `"></iframe></body></html>`,
+			"https://example.com/frame/post": fmt.Sprintf(`<html><head><title>Frame</title></head><body><main><p>%s</p>
+				<iframe src="/post"></iframe></main></body></html>`, body),
+		}}
+	}
+
+	t.Run("same_origin_iframe_is_followed", func(t *testing.T) {
+		fetcher := newFetcher("/frame/post")
+		extractor, err := extract.NewExtractor(fetcher, extract.WithFollowIframes(true))
+		assert.NoError(t, err)
+
+		text, hasBody, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/post")
+
+		assert.NoError(t, err)
+		assert.True(t, hasBody)
+		assert.Equal(t, titlePrefix+"Frame\n\n"+body, text)
+		assert.Equal(t, []string{"https://example.com/post", "https://example.com/frame/post"}, fetcher.fetched, "iframeは1階層のみ辿るべき")
+	})
+
+	t.Run("structured_result_keeps_parent_title", func(t *testing.T) {
+		extractor, err := extract.NewExtractor(newFetcher("/frame/post"), extract.WithFollowIframes(true))
+		assert.NoError(t, err)
+
+		result, err := extractor.FetchAndExtract(context.Background(), "https://example.com/post")
+
+		assert.NoError(t, err)
+		assert.True(t, result.HasBody)
+		assert.Equal(t, "Wrapper", result.Title)
+		assert.Equal(t, body, result.Body)
+	})
+
+	t.Run("structured_result_uses_frame_tables_and_code", func(t *testing.T) {
+		fetcher := &mapFetcher{pages: map[string]string{
+			"https://example.com/post": `<html><head><title>Wrapper</title></head><body><iframe src="/frame/post"></iframe></body></html>`,
+			"https://example.com/frame/post": fmt.Sprintf(`<html><head><title>Frame</title></head><body><main><p>%s</p>
+				<table><tr><td>a</td><td>b</td></tr></table>
+				<pre>go run .</pre></main></body></html>`, body),
+		}}
+		extractor, err := extract.NewExtractor(fetcher, extract.WithFollowIframes(true))
+		assert.NoError(t, err)
+
+		result, err := extractor.FetchAndExtract(context.Background(), "https://example.com/post")
+
+		assert.NoError(t, err)
+		assert.True(t, result.HasBody)
+		assert.Equal(t, "Wrapper", result.Title)
+		assert.Equal(t, body+"\n\na | b\n\n```\ngo run .\n```", result.Body)
+		assert.Equal(t, []string{"a | b"}, result.Tables)
+		assert.Equal(t, []string{"go run ."}, result.CodeBlocks)
+		assert.False(t, result.Relaxed)
+		assert.False(t, result.SoftNotFound)
+	})
+
+	t.Run("cross_origin_iframe_is_ignored", func(t *testing.T) {
+		fetcher := newFetcher("https://ads.example.net/frame/post")
+		extractor, err := extract.NewExtractor(fetcher, extract.WithFollowIframes(true))
+		assert.NoError(t, err)
+
+		_, hasBody, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/post")
+
+		assert.NoError(t, err)
+		assert.False(t, hasBody)
+		assert.Equal(t, []string{"https://example.com/post"}, fetcher.fetched)
+	})
+}
+
+func TestFetchAndExtractText_KeepShortLines(t *testing.T) {
+	const titlePrefix = "【記事タイトル】 "
+	html := `<html><head><title>Transcript</title></head><body><main>
+		<p>Lonely short</p>
+		<div>
+			<p>A: Hello there</p>
+			<p>B: Hi!</p>
+			<p>A: How are you?</p>
+		</div>
+	</main></body></html>`
+
+	t.Run("enabled", func(t *testing.T) {
+		extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html}, extract.WithKeepShortLines(true))
+		assert.NoError(t, err)
+
+		text, hasBody, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/log")
+
+		assert.NoError(t, err)
+		assert.True(t, hasBody)
+		assert.Equal(t, titlePrefix+"Transcript\n\nA: Hello there\n\nB: Hi!\n\nA: How are you?", text)
+	})
+
+	t.Run("disabled_by_default", func(t *testing.T) {
+		extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html})
+		assert.NoError(t, err)
+
+		_, hasBody, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/log")
+
+		assert.NoError(t, err)
+		assert.False(t, hasBody)
+	})
+}
+
+func TestFetchAndExtractText_NormalizeUnicode(t *testing.T) {
+	const titlePrefix = "【記事タイトル】 "
+	html := `<html><head><title>ＡＢＣニュース</title></head><body><main>
+		<p>価格は１２３，４５６円（税込）で、ＡＰＩ経由でも購入できます。</p>
+		<table><tr><td>ｺｰﾄﾞ</td><td>Ｘ１</td></tr></table>
+	</main></body></html>`
+
+	extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html}, extract.WithNormalizeUnicode(true))
+	assert.NoError(t, err)
+
+	text, hasBody, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/nfkc")
+
+	assert.NoError(t, err)
+	assert.True(t, hasBody)
+	assert.Equal(t, titlePrefix+"ABCニュース\n\n価格は123,456円(税込)で、API経由でも購入できます。\n\nコード | X1", text)
+}
+
+func TestFetchAndExtract_Times(t *testing.T) {
+	html := `<html><head><title>Events</title></head><body><main>
+		<p>Conference <time datetime="2024-06-01T10:00:00+09:00">6月1日 10時</time></p>
+		<p>Deadline <time datetime="2024-05-20">May 20</time></p>
+		<p>Meetup <time>2024-07-15 18:30</time></p>
+		<p>Someday <time datetime="soon">soon</time></p>
+	</main></body></html>`
+
+	extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html}, extract.WithExtractTimes(true))
+	assert.NoError(t, err)
+
+	result, err := extractor.FetchAndExtract(context.Background(), "https://example.com/events")
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Times, 3)
+	assert.True(t, result.Times[0].Equal(time.Date(2024, 6, 1, 1, 0, 0, 0, time.UTC)))
+	assert.True(t, result.Times[1].Equal(time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC)))
+	assert.True(t, result.Times[2].Equal(time.Date(2024, 7, 15, 18, 30, 0, 0, time.UTC)))
+}
+
+func TestFetchAndExtractText_TableStyle(t *testing.T) {
+	const prefix = "【記事タイトル】 Table\n\n"
+	html := `<html><head><title>Table</title></head><body><main>
+		<table><caption>Prices</caption>
+			<tr><th>Item</th><th>Price</th></tr>
+			<tr><td>りんご</td><td>100</td></tr>
+			<tr><td>Pen, "blue" | ink</td><td>2000</td></tr>
+		</table>
+	</main></body></html>`
+
+	testCases := []struct {
+		name     string
+		style    extract.TableStyle
+		expected string
+	}{
+		{
+			name:     "plain",
+			style:    extract.TableStylePlain,
+			expected: "【表題】 Prices\nItem | Price\nりんご | 100\nPen, \"blue\" | ink | 2000",
+		},
+		{
+			name:     "markdown",
+			style:    extract.TableStyleMarkdown,
+			expected: "**Prices**\n\n| Item | Price |\n| --- | --- |\n| りんご | 100 |\n| Pen, \"blue\" \\| ink | 2000 |",
+		},
+		{
+			name:     "csv",
+			style:    extract.TableStyleCSV,
+			expected: "【表題】 Prices\nItem,Price\nりんご,100\n\"Pen, \"\"blue\"\" | ink\",2000",
+		},
+		{
+			name:     "aligned",
+			style:    extract.TableStyleAligned,
+			expected: "【表題】 Prices\nItem              | Price\nりんご            | 100\nPen, \"blue\" | ink | 2000",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html}, extract.WithTableStyle(tc.style))
+			assert.NoError(t, err)
+
+			text, hasBody, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/table")
+
+			assert.NoError(t, err)
+			assert.True(t, hasBody)
+			assert.Equal(t, prefix+tc.expected, text)
+		})
+	}
+}
+
+func TestFetchAndExtract_FallbackRelaxed(t *testing.T) {
+	html := `<html><head><title>Tiny</title></head><body><main>
+		<h2>Hi</h2>
+		<p>Short note.</p>
+	</main></body></html>`
+
+	t.Run("relaxed_pass_recovers_body", func(t *testing.T) {
+		extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html}, extract.WithFallbackRelaxed(true))
+		assert.NoError(t, err)
+
+		result, err := extractor.FetchAndExtract(context.Background(), "https://example.com/tiny")
+
+		assert.NoError(t, err)
+		assert.True(t, result.HasBody)
+		assert.True(t, result.Relaxed)
+		assert.Equal(t, "## Hi\n\nShort note.", result.Body)
+
+		text, hasBody, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/tiny")
+		assert.NoError(t, err)
+		assert.True(t, hasBody)
+		assert.Equal(t, "【記事タイトル】 Tiny\n\n## Hi\n\nShort note.", text)
+	})
+
+	t.Run("strict_pass_is_not_marked_relaxed", func(t *testing.T) {
+		body := "This paragraph is long enough to be treated as extracted article body."
+		extractor, err := extract.NewExtractor(
+			&MockFetcher{htmlContent: fmt.Sprintf(`<html><body><main><p>%s</p><p>Short note.</p></main></body></html>`, body)},
+			extract.WithFallbackRelaxed(true),
+		)
+		assert.NoError(t, err)
+
+		result, err := extractor.FetchAndExtract(context.Background(), "https://example.com/strict")
+
+		assert.NoError(t, err)
+		assert.False(t, result.Relaxed)
+		assert.Equal(t, body, result.Body)
+	})
+}
+
+func TestFetchAndExtractText_MaxHTMLBytes(t *testing.T) {
+	const titlePrefix = "【記事タイトル】 "
+	first := "最初の段落はページの先頭付近にあり、十分な長さを持っています。"
+	head := fmt.Sprintf(`<html><head><title>Big</title></head><body><main><p>%s</p>`, first)
+	html := head + `<p>二番目の段落は上限を超えた位置にあるため切り捨てられます。</p></main></body></html>`
+
+	testCases := []struct {
+		name  string
+		limit int
+	}{
+		{name: "cut_inside_tag", limit: len(head) + 2},
+		{name: "cut_inside_multibyte_rune", limit: len(head) + len("<p>") + 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html}, extract.WithMaxHTMLBytes(tc.limit))
+			assert.NoError(t, err)
+
+			text, hasBody, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/big")
+
+			assert.NoError(t, err)
+			assert.True(t, hasBody)
+			assert.Equal(t, titlePrefix+"Big\n\n"+first, text)
+		})
+	}
+}
+
+func TestFetchAndExtract_TitleSource(t *testing.T) {
+	html := `<html><head>
+		<title>Launch Day — Example News</title>
+		<meta property="og:title" content="Launch Day | Example News">
+		<meta property="og:site_name" content="Example News">
+		<meta name="twitter:title" content="Launch (twitter)">
+	</head><body><main>
+		<h1>Launch  Day Headline</h1>
+		<p>This paragraph is long enough to be treated as extracted article body.</p>
+	</main></body></html>`
+
+	testCases := []struct {
+		name     string
+		opts     []extract.Option
+		expected string
+	}{
+		{name: "default_prefers_og_title", expected: "Launch Day | Example News"},
+		{
+			name:     "title_element_only",
+			opts:     []extract.Option{extract.WithTitleSource([]string{extract.TitleSourceTitle})},
+			expected: "Launch Day — Example News",
+		},
+		{
+			name:     "og_title_first",
+			opts:     []extract.Option{extract.WithTitleSource([]string{extract.TitleSourceOGTitle, extract.TitleSourceTitle})},
+			expected: "Launch Day | Example News",
+		},
+		{
+			name:     "h1_first",
+			opts:     []extract.Option{extract.WithTitleSource([]string{extract.TitleSourceH1})},
+			expected: "Launch Day Headline",
+		},
+		{
+			name:     "missing_source_falls_through",
+			opts:     []extract.Option{extract.WithTitleSource([]string{"unknown", extract.TitleSourceTwitterTitle})},
+			expected: "Launch (twitter)",
+		},
+		{
+			name:     "strip_site_suffix",
+			opts:     []extract.Option{extract.WithStripSiteSuffix(true)},
+			expected: "Launch Day",
+		},
+		{
+			name: "strip_site_suffix_with_og_title",
+			opts: []extract.Option{
+				extract.WithTitleSource([]string{extract.TitleSourceOGTitle}),
+				extract.WithStripSiteSuffix(true),
+			},
+			expected: "Launch Day",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html}, tc.opts...)
+			assert.NoError(t, err)
+
+			result, err := extractor.FetchAndExtract(context.Background(), "https://example.com/launch")
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, result.Title)
+		})
+	}
+
+	t.Run("title_candidates", func(t *testing.T) {
+		extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html},
+			extract.WithTitleSource([]string{"unknown", extract.TitleSourceOGTitle}),
+			extract.WithStripSiteSuffix(true),
+		)
+		assert.NoError(t, err)
+
+		result, err := extractor.FetchAndExtract(context.Background(), "https://example.com/launch")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []extract.TitleCandidate{
+			{Source: extract.TitleSourceTitle, Value: "Launch Day — Example News"},
+			{
+				Source: extract.TitleSourceOGTitle,
+				Value:  "Launch Day | Example News",
+				Chosen: true,
+				Reason: "優先順位 2 番目の取得元で最初に値が得られました (サイト名の接尾辞を除去)",
+			},
+			{Source: extract.TitleSourceTwitterTitle, Value: "Launch (twitter)"},
+			{Source: extract.TitleSourceH1, Value: "Launch Day Headline"},
+			{Source: extract.TitleSourceJSONLD},
+		}, result.TitleCandidates)
+	})
+
+	t.Run("suffix_kept_without_site_name", func(t *testing.T) {
+		extractor, err := extract.NewExtractor(
+			&MockFetcher{htmlContent: `<html><head><title>Article - Blog</title></head><body></body></html>`},
+			extract.WithStripSiteSuffix(true),
+		)
+		assert.NoError(t, err)
+
+		result, err := extractor.FetchAndExtract(context.Background(), "https://example.com/a")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Article - Blog", result.Title)
+	})
+}
+
+func TestFetchAndExtract_Breadcrumbs(t *testing.T) {
+	body := `<main><p>This paragraph is long enough to be treated as extracted article body.</p></main>`
+
+	testCases := []struct {
+		name     string
+		html     string
+		expected []string
+	}{
+		{
+			name: "nav_aria_label",
+			html: `<html><head><title>A</title></head><body>
+				<nav aria-label="Breadcrumb"><ol><li><a href="/">Home</a></li><li>›</li><li><a href="/tech">Tech</a></li><li>AI</li></ol></nav>` + body + `</body></html>`,
+			expected: []string{"Home", "Tech", "AI"},
+		},
+		{
+			name: "nav_aria_label_plural",
+			html: `<html><head><title>A</title></head><body>
+				<nav aria-label="Breadcrumbs"><ol><li><a href="/">Home</a></li><li>Docs</li></ol></nav>` + body + `</body></html>`,
+			expected: []string{"Home", "Docs"},
+		},
+		{
+			name: "nav_aria_label_uppercase_with_suffix",
+			html: `<html><head><title>A</title></head><body>
+				<nav aria-label="BREADCRUMB NAVIGATION"><ol><li><a href="/">Home</a></li><li>Blog</li></ol></nav>` + body + `</body></html>`,
+			expected: []string{"Home", "Blog"},
+		},
+		{
+			name: "nav_aria_label_japanese",
+			html: `<html><head><title>A</title></head><body>
+				<nav aria-label="パンくずリスト"><ol><li><a href="/">ホーム</a></li><li>ニュース</li></ol></nav>` + body + `</body></html>`,
+			expected: []string{"ホーム", "ニュース"},
+		},
+		{
+			name:     "nav_aria_label_other",
+			html:     `<html><head><title>A</title></head><body><nav aria-label="Main menu"><ol><li>Home</li><li>Docs</li></ol></nav>` + body + `</body></html>`,
+			expected: nil,
+		},
+		{
+			name: "breadcrumb_class_with_links",
+			html: `<html><head><title>A</title></head><body>
+				<div class="breadcrumb"><a href="/">Home</a> &gt; <a href="/news">News</a></div>` + body + `</body></html>`,
+			expected: []string{"Home", "News"},
+		},
+		{
+			name: "microdata",
+			html: `<html><head><title>A</title></head><body>
+				<ol itemscope itemtype="https://schema.org/BreadcrumbList">
+					<li itemprop="itemListElement" itemscope itemtype="https://schema.org/ListItem"><a itemprop="item" href="/"><span itemprop="name">Home</span></a></li>
+					<li itemprop="itemListElement" itemscope itemtype="https://schema.org/ListItem"><span itemprop="name">Sports</span></li>
+				</ol>` + body + `</body></html>`,
+			expected: []string{"Home", "Sports"},
+		},
+		{
+			name: "json_ld_preferred",
+			html: `<html><head><title>A</title>
+				<script type="application/ld+json">{"@context":"https://schema.org","@graph":[{"@type":"WebPage"},{"@type":"BreadcrumbList","itemListElement":[
+					{"@type":"ListItem","position":2,"item":{"@id":"/tech","name":"Tech"}},
+					{"@type":"ListItem","position":1,"name":"Home","item":"/"}
+				]}]}</script>
+				</head><body><nav aria-label="breadcrumb"><ol><li>Other</li></ol></nav>` + body + `</body></html>`,
+			expected: []string{"Home", "Tech"},
+		},
+		{
+			name:     "none",
+			html:     `<html><head><title>A</title></head><body><nav><a href="/">Menu</a></nav>` + body + `</body></html>`,
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: tc.html}, extract.WithExtractBreadcrumbs(true))
+			assert.NoError(t, err)
+
+			result, err := extractor.FetchAndExtract(context.Background(), "https://example.com/a")
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, result.Breadcrumbs)
+			assert.True(t, result.HasBody)
+		})
+	}
+}
+
+func TestFetchAndExtract_SocialMetadata(t *testing.T) {
+	body := `<main><p>This paragraph is long enough to be treated as extracted article body.</p></main>`
+
+	t.Run("og_takes_precedence_over_twitter", func(t *testing.T) {
+		html := `<html><head><title>A</title>
+			<meta property="og:title" content="OG Title">
+			<meta name="twitter:title" content="Twitter Title">
+			<meta name="twitter:description" content="Twitter description">
+			<meta name="twitter:image" content="/img/card.png">
+			<meta name="twitter:card" content="summary_large_image">
+		</head><body>` + body + `</body></html>`
+		extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html})
+		assert.NoError(t, err)
+
+		result, err := extractor.FetchAndExtract(context.Background(), "https://example.com/posts/1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, extract.SocialMetadata{
+			Title:       "OG Title",
+			Description: "Twitter description",
+			Image:       "https://example.com/img/card.png",
+			Card:        "summary_large_image",
+		}, result.Social)
+	})
+
+	t.Run("no_metadata", func(t *testing.T) {
+		extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: `<html><head><title>A</title></head><body>` + body + `</body></html>`})
+		assert.NoError(t, err)
+
+		result, err := extractor.FetchAndExtract(context.Background(), "https://example.com/posts/1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, extract.SocialMetadata{}, result.Social)
+	})
+}
+
+func TestFetchAndExtractText_DropPhrases(t *testing.T) {
+	const titlePrefix = "【記事タイトル】 "
+	body := "This paragraph is long enough to be treated as extracted article body."
+	html := fmt.Sprintf(`<html><head><title>A</title></head><body><main>
+		<p>%s</p>
+		<p>SHARE THIS ARTICLE on Twitter</p>
+		<p>Sponsored content appears below this line.</p>
+		<ul><li>Advertisement</li></ul>
+	</main></body></html>`, body)
+
+	testCases := []struct {
+		name     string
+		opts     []extract.Option
+		expected string
+	}{
+		{
+			name:     "disabled_by_default",
+			expected: titlePrefix + "A\n\n" + body + "\n\nSHARE THIS ARTICLE on Twitter\n\nSponsored content appears below this line.\n\nAdvertisement",
+		},
+		{
+			name:     "default_phrases_are_case_sensitive",
+			opts:     []extract.Option{extract.WithDefaultDropPhrases(true)},
+			expected: titlePrefix + "A\n\n" + body + "\n\nSHARE THIS ARTICLE on Twitter\n\nSponsored content appears below this line.",
+		},
+		{
+			name: "default_and_custom_phrases_ignoring_case",
+			opts: []extract.Option{
+				extract.WithDefaultDropPhrases(true),
+				extract.WithDropPhrases([]string{"sponsored  content"}),
+				extract.WithDropPhrasesIgnoreCase(true),
+			},
+			expected: titlePrefix + "A\n\n" + body,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html}, tc.opts...)
+			assert.NoError(t, err)
+
+			text, hasBody, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/a")
+
+			assert.NoError(t, err)
+			assert.True(t, hasBody)
+			assert.Equal(t, tc.expected, text)
+		})
+	}
+
+	t.Run("default_phrases_keep_paragraphs_mentioning_them", func(t *testing.T) {
+		paragraph := "今年の広告市場はインターネット広告の伸びに支えられ、前年を上回る規模となりました。"
+		extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: `<html><head><title>A</title></head><body><main>
+			<p>` + paragraph + `</p>
+		</main></body></html>`}, extract.WithDefaultDropPhrases(true))
+		assert.NoError(t, err)
+
+		text, _, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/a")
+
+		assert.NoError(t, err)
+		assert.Equal(t, titlePrefix+"A\n\n"+paragraph, text)
+	})
+}
+
+func TestFetchAndExtractText_MinTableSize(t *testing.T) {
+	const titlePrefix = "【記事タイトル】 "
+	html := `<html><head><title>A</title></head><body><main>
+		<table><tr><td>Layout wrapper cell</td></tr></table>
+		<table><tr><th>Name</th><th>Score</th></tr><tr><td>Alice</td><td>90</td></tr></table>
+	</main></body></html>`
+
+	testCases := []struct {
+		name     string
+		opts     []extract.Option
+		expected string
+	}{
+		{
+			name:     "defaults_keep_all_tables",
+			expected: titlePrefix + "A\n\nLayout wrapper cell\n\nName | Score\nAlice | 90",
+		},
+		{
+			name:     "single_cell_table_filtered_by_rows",
+			opts:     []extract.Option{extract.WithMinTableRows(2)},
+			expected: titlePrefix + "A\n\nName | Score\nAlice | 90",
+		},
+		{
+			name:     "single_cell_table_filtered_by_columns",
+			opts:     []extract.Option{extract.WithMinTableColumns(2)},
+			expected: titlePrefix + "A\n\nName | Score\nAlice | 90",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html}, tc.opts...)
+			assert.NoError(t, err)
+
+			text, hasBody, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/a")
+
+			assert.NoError(t, err)
+			assert.True(t, hasBody)
+			assert.Equal(t, tc.expected, text)
+		})
+	}
+}
+
+func TestFetchAndExtractTextWith(t *testing.T) {
+	const titlePrefix = "【記事タイトル】 "
+	body := "This paragraph is long enough to be treated as extracted article body."
+	html := fmt.Sprintf(`<html><head><title>A</title></head><body><main><h3>Section</h3><p>%s</p></main></body></html>`, body)
+
+	extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html})
+	assert.NoError(t, err)
+
+	t.Run("call_scoped_options_apply", func(t *testing.T) {
+		text, hasBody, err := extractor.FetchAndExtractTextWith(context.Background(), "https://example.com/a",
+			extract.WithOutputFormat(extract.FormatMarkdown))
+
+		assert.NoError(t, err)
+		assert.True(t, hasBody)
+		assert.Equal(t, titlePrefix+"A\n\n### Section\n\n"+body, text)
+	})
+
+	t.Run("defaults_are_not_mutated_concurrently", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				minLength := map[int]int{3: 100}
+				if i%2 == 0 {
+					minLength = nil
+				}
+				_, _, err := extractor.FetchAndExtractTextWith(context.Background(), "https://example.com/a",
+					extract.WithHeadingMinLengths(minLength))
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		text, _, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/a")
+		assert.NoError(t, err)
+		assert.Equal(t, titlePrefix+"A\n\n## Section\n\n"+body, text)
+	})
+}
+
+func TestExtractMany(t *testing.T) {
+	const titlePrefix = "【記事タイトル】 "
+	body := "This paragraph is long enough to be treated as extracted article body."
+	page := func(title string) []byte {
+		return []byte(fmt.Sprintf(`<html><head><title>%s</title></head><body><main><p>%s</p></main></body></html>`, title, body))
+	}
+
+	extractor, err := extract.NewExtractor(&MockFetcher{}, extract.WithConcurrentExtraction(2))
+	assert.NoError(t, err)
+
+	t.Run("results_keep_input_order", func(t *testing.T) {
+		docs := [][]byte{page("One"), []byte(""), page("Two"), page("Three")}
+
+		results := extractor.ExtractMany(context.Background(), docs)
+
+		assert.Len(t, results, 4)
+		assert.Equal(t, titlePrefix+"One\n\n"+body, results[0].Text)
+		assert.True(t, results[0].HasBody)
+		assert.Error(t, results[1].Error)
+		assert.Equal(t, titlePrefix+"Two\n\n"+body, results[2].Text)
+		assert.Equal(t, titlePrefix+"Three\n\n"+body, results[3].Text)
+	})
+
+	t.Run("canceled_context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		results := extractor.ExtractMany(ctx, [][]byte{page("One"), page("Two")})
+
+		for _, res := range results {
+			assert.ErrorIs(t, res.Error, context.Canceled)
+		}
+	})
+}
+
+func TestExtractTextWithContentType_AllowedContentTypes(t *testing.T) {
+	extractor, err := extract.NewExtractor(&MockFetcher{},
+		extract.WithAllowedContentTypes("text/html", "application/xhtml+xml", "text/plain"))
+	assert.NoError(t, err)
+
+	t.Run("plain_text_is_kept_preformatted", func(t *testing.T) {
+		text, hasBody, err := extractor.ExtractTextWithContentType(context.Background(),
+			strings.NewReader("  line one\r\n    indented <b>not html</b>\n"), "text/plain; charset=utf-8")
+
+		assert.NoError(t, err)
+		assert.True(t, hasBody)
+		assert.Equal(t, "line one\n    indented <b>not html</b>", text)
+	})
+
+	t.Run("disallowed_type", func(t *testing.T) {
+		_, _, err := extractor.ExtractTextWithContentType(context.Background(), strings.NewReader("{}"), "application/json")
+
+		assert.ErrorIs(t, err, extract.ErrUnsupportedContentType)
+	})
+
+	t.Run("html_still_parsed", func(t *testing.T) {
+		body := "This paragraph is long enough to be treated as extracted article body."
+		html := fmt.Sprintf(`<html><head><title>A</title></head><body><main><p>%s</p></main></body></html>`, body)
+
+		text, hasBody, err := extractor.ExtractTextWithContentType(context.Background(), strings.NewReader(html), "application/xhtml+xml")
+
+		assert.NoError(t, err)
+		assert.True(t, hasBody)
+		assert.Equal(t, "【記事タイトル】 A\n\n"+body, text)
+	})
+
+	t.Run("unrestricted_by_default", func(t *testing.T) {
+		defaultExtractor, err := extract.NewExtractor(&MockFetcher{})
+		assert.NoError(t, err)
+
+		_, _, err = defaultExtractor.ExtractTextWithContentType(context.Background(), strings.NewReader("<p>x</p>"), "application/json")
+
+		assert.NotErrorIs(t, err, extract.ErrUnsupportedContentType)
+	})
+}
+
+func TestFetchAndExtractParts(t *testing.T) {
+	const titlePrefix = "【記事タイトル】 "
+	body := "This paragraph is long enough to be treated as extracted article body."
+	html := fmt.Sprintf(`<html><head><title>A</title></head><body><main>
+		<p>%s</p>
+		<pre>func main() {
+
+	run()
+}</pre>
+	</main></body></html>`, body)
+
+	extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html})
+	assert.NoError(t, err)
+
+	parts, hasBody, err := extractor.FetchAndExtractParts(context.Background(), "https://example.com/a")
+
+	assert.NoError(t, err)
+	assert.True(t, hasBody)
+	assert.Equal(t, []string{
+		titlePrefix + "A",
+		body,
+		"```\nfunc main() {\n\n\trun()\n}\n```",
+	}, parts)
+
+	text, _, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/a")
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Join(parts, "\n\n"), text)
+}
+
+func TestFetchAndExtractText_DetectSoft404(t *testing.T) {
+	body := "This paragraph is long enough to be treated as extracted article body."
+	testCases := []struct {
+		name     string
+		html     string
+		opts     []extract.Option
+		expected bool
+	}{
+		{
+			name:     "title_match",
+			html:     `<html><head><title>404 Not Found | Example</title></head><body><main><p>` + body + `</p></main></body></html>`,
+			expected: true,
+		},
+		{
+			name:     "japanese_short_body",
+			html:     `<html><head><title>Example</title></head><body><main><p>申し訳ありません。お探しのページは見つかりませんでした。</p></main></body></html>`,
+			expected: true,
+		},
+		{
+			name:     "long_article_mentioning_keyword",
+			html:     `<html><head><title>Debugging</title></head><body><main><p>` + strings.Repeat(body+" ", 10) + `The server answered with page not found.</p></main></body></html>`,
+			expected: false,
+		},
+		{
+			name:     "long_article_with_keyword_in_title",
+			html:     `<html><head><title>How to fix a 404 error in Nginx</title></head><body><main><p>` + strings.Repeat(body+" ", 10) + `</p></main></body></html>`,
+			expected: false,
+		},
+		{
+			name:     "custom_keywords",
+			html:     `<html><head><title>Gone fishing</title></head><body><main><p>` + body + `</p></main></body></html>`,
+			opts:     []extract.Option{extract.WithSoft404Keywords([]string{"Gone Fishing"})},
+			expected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := append([]extract.Option{extract.WithDetectSoft404(true)}, tc.opts...)
+			extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: tc.html}, opts...)
+			assert.NoError(t, err)
+
+			_, _, err = extractor.FetchAndExtractText(context.Background(), "https://example.com/a")
+			assert.Equal(t, tc.expected, errors.Is(err, extract.ErrSoftNotFound))
+
+			result, err := extractor.FetchAndExtract(context.Background(), "https://example.com/a")
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, result.SoftNotFound)
+		})
+	}
+
+	t.Run("disabled_by_default", func(t *testing.T) {
+		extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: testCases[0].html})
+		assert.NoError(t, err)
+
+		_, hasBody, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/a")
+
+		assert.NoError(t, err)
+		assert.True(t, hasBody)
+	})
+}
+
+func TestFetchAndExtractText_PreserveCodeIndentation(t *testing.T) {
+	const titlePrefix = "【記事タイトル】 "
+	html := "<html><head><title>A</title></head><body><main><pre>\n\n    def run():\n        return 1\n  \n</pre></main></body></html>"
+
+	testCases := []struct {
+		name     string
+		opts     []extract.Option
+		expected string
+	}{
+		{
+			name:     "default_trims_all_outer_whitespace",
+			expected: titlePrefix + "A\n\n```\ndef run():\n        return 1\n```",
+		},
+		{
+			name:     "preserve_indentation",
+			opts:     []extract.Option{extract.WithPreserveCodeIndentation(true)},
+			expected: titlePrefix + "A\n\n```\n    def run():\n        return 1\n```",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html}, tc.opts...)
+			assert.NoError(t, err)
+
+			text, hasBody, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/a")
+
+			assert.NoError(t, err)
+			assert.True(t, hasBody)
+			assert.Equal(t, tc.expected, text)
+		})
+	}
+}
+
+func TestFetchAndExtractText_CodeLanguage(t *testing.T) {
+	const titlePrefix = "【記事タイトル】 "
+
+	testCases := []struct {
+		name     string
+		pre      string
+		expected string
+	}{
+		{
+			name:     "language_class_on_code",
+			pre:      `<pre><code class="hljs language-python">print("hi")</code></pre>`,
+			expected: "```python\nprint(\"hi\")\n```",
+		},
+		{
+			name:     "lang_class_on_pre",
+			pre:      `<pre class="prettyprint lang-go">fmt.Println("hi")</pre>`,
+			expected: "```go\nfmt.Println(\"hi\")\n```",
+		},
+		{
+			name:     "plain_pre",
+			pre:      `<pre>echo hi</pre>`,
+			expected: "```\necho hi\n```",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			html := `<html><head><title>A</title></head><body><main>` + tc.pre + `</main></body></html>`
+			extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html})
+			assert.NoError(t, err)
+
+			text, _, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/a")
+
+			assert.NoError(t, err)
+			assert.Equal(t, titlePrefix+"A\n\n"+tc.expected, text)
+		})
+	}
+}
+
+func TestFetchAndExtractText_EmojiHandling(t *testing.T) {
+	const titlePrefix = "【記事タイトル】 "
+	html := `<html><head><title>Launch 🚀</title></head><body><main>
+		<p>Great news for the 👨‍👩‍👧 family today 👍🏽 from 🇯🇵 with love ❤️ and more.</p>
+	</main></body></html>`
+
+	testCases := []struct {
+		name     string
+		mode     extract.EmojiHandling
+		expected string
+	}{
+		{
+			name:     "keep",
+			mode:     extract.EmojiKeep,
+			expected: titlePrefix + "Launch 🚀\n\nGreat news for the 👨‍👩‍👧 family today 👍🏽 from 🇯🇵 with love ❤️ and more.",
+		},
+		{
+			name:     "strip_whole_sequences",
+			mode:     extract.EmojiStrip,
+			expected: titlePrefix + "Launch\n\nGreat news for the family today from with love and more.",
+		},
+		{
+			name: "describe",
+			mode: extract.EmojiDescribe,
+			expected: titlePrefix + "Launch :rocket:\n\nGreat news for the :family_man_woman_girl: family today " +
+				":thumbs_up_medium_skin_tone: from :flag_japan: with love :red_heart: and more.",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html}, extract.WithEmojiHandling(tc.mode))
+			assert.NoError(t, err)
+
+			text, hasBody, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/a")
+
+			assert.NoError(t, err)
+			assert.True(t, hasBody)
+			assert.Equal(t, tc.expected, text)
+		})
+	}
+}
+
+func TestFetchAndExtractText_EmojiHandlingKeepsTextPresentationSymbols(t *testing.T) {
+	const titlePrefix = "【記事タイトル】 "
+	html := `<html><head><title>Notice</title></head><body><main>
+		<p>Copyright © 2024 Example Corp ™ ® with a launch 🚀 and a keycap 1️⃣ today.</p>
+	</main></body></html>`
+
+	testCases := []struct {
+		name     string
+		mode     extract.EmojiHandling
+		expected string
+	}{
+		{
+			name:     "strip",
+			mode:     extract.EmojiStrip,
+			expected: titlePrefix + "Notice\n\nCopyright © 2024 Example Corp ™ ® with a launch and a keycap today.",
+		},
+		{
+			name:     "describe",
+			mode:     extract.EmojiDescribe,
+			expected: titlePrefix + "Notice\n\nCopyright © 2024 Example Corp ™ ® with a launch :rocket: and a keycap :keycap_1: today.",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html}, extract.WithEmojiHandling(tc.mode))
+			assert.NoError(t, err)
+
+			text, hasBody, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/a")
+
+			assert.NoError(t, err)
+			assert.True(t, hasBody)
+			assert.Equal(t, tc.expected, text)
+		})
+	}
+}
+
+func TestFetchAndExtract_SectionAndTags(t *testing.T) {
+	body := `<main><p>This paragraph is long enough to be treated as extracted article body.</p></main>`
+
+	t.Run("declared", func(t *testing.T) {
+		html := `<html><head><title>A</title>
+			<meta property="article:section" content="Technology">
+			<meta property="article:tag" content="AI">
+			<meta property="article:tag" content=" Robotics ">
+			<meta property="article:tag" content="AI">
+		</head><body>` + body + `</body></html>`
+		extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html})
+		assert.NoError(t, err)
+
+		result, err := extractor.FetchAndExtract(context.Background(), "https://example.com/a")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Technology", result.Section)
+		assert.Equal(t, []string{"AI", "Robotics"}, result.Tags)
+	})
+
+	t.Run("absent", func(t *testing.T) {
+		extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: `<html><head><title>A</title></head><body>` + body + `</body></html>`})
+		assert.NoError(t, err)
+
+		result, err := extractor.FetchAndExtract(context.Background(), "https://example.com/a")
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.Section)
+		assert.Empty(t, result.Tags)
+	})
+}
+
+func TestFetchAndExtract_DescriptionAndOGImage(t *testing.T) {
+	body := `<main><p>This paragraph is long enough to be treated as extracted article body.</p></main>`
+
+	t.Run("declared", func(t *testing.T) {
+		html := `<html><head><title>Page Title</title>
+			<meta name="description" content="Meta description">
+			<meta property="og:title" content="OG Title">
+			<meta property="og:description" content=" OG description ">
+			<meta property="og:image" content="/images/cover.png">
+		</head><body>` + body + `</body></html>`
+		extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html})
+		assert.NoError(t, err)
+
+		result, err := extractor.FetchAndExtract(context.Background(), "https://example.com/posts/1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "OG Title", result.Title)
+		assert.Equal(t, "OG description", result.Description)
+		assert.Equal(t, "https://example.com/images/cover.png", result.OGImage)
+		assert.Equal(t, extract.TitleCandidate{Source: extract.TitleSourceTitle, Value: "Page Title"}, result.TitleCandidates[0])
+
+		// テキスト抽出のタイトルは従来どおり title 要素です
+		text, _, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/posts/1")
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(text, "【記事タイトル】 Page Title"))
+	})
+
+	t.Run("meta_description_only", func(t *testing.T) {
+		html := `<html><head><title>Page Title</title>
+			<meta name="description" content="Meta description">
+			<meta name="twitter:image" content="https://cdn.example.com/card.png">
+		</head><body>` + body + `</body></html>`
+		extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html})
+		assert.NoError(t, err)
+
+		result, err := extractor.FetchAndExtract(context.Background(), "https://example.com/posts/1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Page Title", result.Title)
+		assert.Equal(t, "Meta description", result.Description)
+		assert.Empty(t, result.OGImage)
+		assert.Equal(t, "https://cdn.example.com/card.png", result.Social.Image)
+	})
+
+	t.Run("absent", func(t *testing.T) {
+		extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: `<html><head><title>Page Title</title></head><body>` + body + `</body></html>`})
+		assert.NoError(t, err)
+
+		result, err := extractor.FetchAndExtract(context.Background(), "https://example.com/posts/1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Page Title", result.Title)
+		assert.Empty(t, result.Description)
+		assert.Empty(t, result.OGImage)
+	})
+}
+
+func TestFetchAndExtractText_MaxParagraphs(t *testing.T) {
+	const titlePrefix = "【記事タイトル】 "
+	p1 := "The first paragraph is long enough to be treated as article body."
+	p2 := "The second paragraph is long enough to be treated as article body."
+	p3 := "The third paragraph is long enough to be treated as article body."
+	html := fmt.Sprintf(`<html><head><title>A</title></head><body><main>
+		<p>%s</p><p>%s</p><h2>Details</h2><p>%s</p><table><tr><td>x</td><td>1</td></tr></table>
+	</main></body></html>`, p1, p2, p3)
+
+	testCases := []struct {
+		name     string
+		opts     []extract.Option
+		expected string
+	}{
+		{
+			name:     "unlimited_by_default",
+			expected: titlePrefix + "A\n\n" + p1 + "\n\n" + p2 + "\n\n## Details\n\n" + p3 + "\n\nx | 1",
+		},
+		{
+			name:     "paragraphs_capped_others_kept",
+			opts:     []extract.Option{extract.WithMaxParagraphs(1)},
+			expected: titlePrefix + "A\n\n" + p1 + "\n\n## Details\n\nx | 1",
+		},
+		{
+			name:     "stop_collecting_at_cap",
+			opts:     []extract.Option{extract.WithMaxParagraphs(2), extract.WithStopAtMaxParagraphs(true)},
+			expected: titlePrefix + "A\n\n" + p1 + "\n\n" + p2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html}, tc.opts...)
+			assert.NoError(t, err)
+
+			text, hasBody, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/a")
+
+			assert.NoError(t, err)
+			assert.True(t, hasBody)
+			assert.Equal(t, tc.expected, text)
+		})
+	}
+}
+
+func TestFetchAndExtract_TablesAndCodeBlocks(t *testing.T) {
+	body := "This paragraph is long enough to be treated as extracted article body."
+	html := fmt.Sprintf(`<html><head><title>A</title></head><body><main>
+		<p>%s</p>
+		<table><tr><th>Name</th><th>Score</th></tr><tr><td>Alice</td><td>90</td></tr></table>
+		<pre>go test ./...</pre>
+	</main></body></html>`, body)
+
+	extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html})
+	assert.NoError(t, err)
+
+	result, err := extractor.FetchAndExtract(context.Background(), "https://example.com/a")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Name | Score\nAlice | 90"}, result.Tables)
+	assert.Equal(t, []string{"go test ./..."}, result.CodeBlocks)
+	assert.Equal(t, body+"\n\nName | Score\nAlice | 90\n\n```\ngo test ./...\n```", result.Body)
+}
+
+func TestFetchAndExtractText_MarkdownTables(t *testing.T) {
+	const titlePrefix = "【記事タイトル】 "
+	html := `<html><head><title>A</title></head><body><main>
+		<h3>Scores</h3>
+		<table><tr><th>Name</th><th>Score</th></tr><tr><td>Alice</td><td>90</td></tr></table>
+	</main></body></html>`
+
+	testCases := []struct {
+		name     string
+		opts     []extract.Option
+		expected string
+	}{
+		{
+			name:     "markdown_defaults_to_gfm_tables",
+			opts:     []extract.Option{extract.WithOutputFormat(extract.FormatMarkdown)},
+			expected: titlePrefix + "A\n\n### Scores\n\n| Name | Score |\n| --- | --- |\n| Alice | 90 |",
+		},
+		{
+			name: "explicit_table_style_wins",
+			opts: []extract.Option{
+				extract.WithOutputFormat(extract.FormatMarkdown),
+				extract.WithTableStyle(extract.TableStylePlain),
+			},
+			expected: titlePrefix + "A\n\n### Scores\n\nName | Score\nAlice | 90",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html}, tc.opts...)
+			assert.NoError(t, err)
+
+			text, hasBody, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/a")
+
+			assert.NoError(t, err)
+			assert.True(t, hasBody)
+			assert.Equal(t, tc.expected, text)
+		})
+	}
+}
+
+func TestFetchAndExtractText_MarkdownTableHeader(t *testing.T) {
+	const titlePrefix = "【記事タイトル】 "
+	html := `<html><head><title>A</title></head><body><main>
+		<table><caption>Q1 | Q2 results</caption>
+			<tr><td>Updated</td><td>2024-04-01</td></tr>
+			<tr><th>Name</th><th>A|B</th></tr>
+			<tr><td>Alice</td><td>90</td></tr>
+		</table>
+	</main></body></html>`
+	extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html}, extract.WithOutputFormat(extract.FormatMarkdown))
+	assert.NoError(t, err)
+
+	text, _, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/a")
+
+	assert.NoError(t, err)
+	assert.Equal(t, titlePrefix+"A\n\n"+
+		"**Q1 | Q2 results**\n\n"+
+		"| Name | A\\|B |\n"+
+		"| --- | --- |\n"+
+		"| Updated | 2024-04-01 |\n"+
+		"| Alice | 90 |", text)
+}
+
+func TestFetchAndExtractText_LengthCountsRunes(t *testing.T) {
+	const titlePrefix = "【記事タイトル】 "
+	// 7文字 (21バイト) の日本語段落は除外され、21文字の英語段落は採用されるのだ
+	shortJapanese := "短い日本語の文"
+	english := "Twenty-one characters"
+	html := fmt.Sprintf(`<html><head><title>A</title></head><body><main>
+		<h2>見出し</h2><h2>見出し4</h2><p>%s</p><p>%s</p>
+	</main></body></html>`, shortJapanese, english)
+
+	extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html})
+	assert.NoError(t, err)
+
+	text, hasBody, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/a")
+
+	assert.NoError(t, err)
+	assert.True(t, hasBody)
+	assert.Equal(t, titlePrefix+"A\n\n## 見出し4\n\n"+english, text)
+}
+
+func TestExtractLinks(t *testing.T) {
+	html := `<html><head><title>Links</title><base href="https://example.com/docs/"></head><body>
+		<nav><a href="/home">Home</a></nav>
+		<article>
+			<p>See the <a href="guide.html">setup
+				<strong>guide</strong></a> and the <a href="//cdn.example.com/spec.pdf" rel="nofollow">spec</a>.</p>
+			<p><a href="https://example.com/docs/guide.html">duplicate</a>
+			<a href="">empty</a> <a href="javascript:void(0)">js</a> <a href="MAILTO:a@example.com">mail</a></p>
+			<div class="social-share"><a href="https://social.example.com/share">Share</a></div>
+		</article>
+	</body></html>`
+
+	t.Run("main_content_links", func(t *testing.T) {
+		extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html})
+		assert.NoError(t, err)
+
+		links, err := extractor.ExtractLinks(context.Background(), "https://example.com/index.html")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []extract.Link{
+			{AbsoluteURL: "https://example.com/docs/guide.html", AnchorText: "setup guide"},
+			{AbsoluteURL: "https://cdn.example.com/spec.pdf", AnchorText: "spec", Rel: "nofollow"},
+		}, links)
+	})
+
+	t.Run("fetch_error", func(t *testing.T) {
+		extractor, err := extract.NewExtractor(&MockFetcher{fetchError: errors.New("network down")})
+		assert.NoError(t, err)
+
+		links, err := extractor.ExtractLinks(context.Background(), "https://example.com/")
+
+		assert.Error(t, err)
+		assert.Nil(t, links)
+	})
+}
+
+func TestFetchAndExtractText_CustomSelectors(t *testing.T) {
+	const titlePrefix = "【記事タイトル】 "
+	prose := "This paragraph is long enough to be treated as extracted article body."
+	html := `<html><head><title>Selectors</title></head><body>
+		<div class="sidebar-text"><p>This sidebar paragraph is also long enough to be extracted.</p></div>
+		<div class="story">
+			<div class="cookie-banner"><p>We use cookies to improve your experience on this site.</p></div>
+			<p>` + prose + `</p>
+			<div class="comments"><p>This comment paragraph is long enough to be extracted too.</p></div>
+		</div>
+	</body></html>`
+
+	testCases := []struct {
+		name     string
+		opts     []extract.Option
+		expected string
+	}{
+		{
+			name:     "custom_main_and_noise",
+			opts:     []extract.Option{extract.WithMainContentSelectors(".story"), extract.WithNoiseSelectors(".cookie-banner, .comments")},
+			expected: titlePrefix + "Selectors\n\n" + prose,
+		},
+		{
+			name: "noise_override_replaces_defaults",
+			opts: []extract.Option{extract.WithMainContentSelectors(".story"), extract.WithNoiseSelectors(".cookie-banner")},
+			expected: titlePrefix + "Selectors\n\n" + prose + "\n\n" +
+				"This comment paragraph is long enough to be extracted too.",
+		},
+		{
+			name: "empty_falls_back_to_defaults",
+			opts: []extract.Option{extract.WithMainContentSelectors(" "), extract.WithNoiseSelectors("")},
+			expected: titlePrefix + "Selectors\n\n" +
+				"This sidebar paragraph is also long enough to be extracted.\n\n" +
+				"We use cookies to improve your experience on this site.\n\n" + prose,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html}, tc.opts...)
+			assert.NoError(t, err)
+
+			text, hasBody, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/a")
+
+			assert.NoError(t, err)
+			assert.True(t, hasBody)
+			assert.Equal(t, tc.expected, text)
+		})
+	}
+}
+
+func TestFetchAndExtractText_ContentDetection(t *testing.T) {
+	const titlePrefix = "【記事タイトル】 "
+	paragraphs := []string{
+		"The first paragraph of the post explains, in some detail, why the change was made.",
+		"The second paragraph, which is also long, walks through the implementation step by step.",
+	}
+	comment := "Great post, thanks for sharing this with everyone here!"
+	html := `<html><head><title>Blog</title></head><body>
+		<div class="wrapper">
+			<div class="post-body"><p>` + paragraphs[0] + `</p><p>` + paragraphs[1] + `</p></div>
+			<div id="comment-list">
+				<div class="comment"><p>` + comment + `</p></div>
+				<div class="comment"><p>` + comment + ` Really.</p></div>
+			</div>
+		</div>
+	</body></html>`
+	post := titlePrefix + "Blog\n\n" + paragraphs[0] + "\n\n" + paragraphs[1]
+
+	testCases := []struct {
+		name     string
+		opts     []extract.Option
+		html     string
+		expected string
+	}{
+		{
+			name:     "default_includes_comments",
+			html:     html,
+			expected: post + "\n\n" + comment + "\n\n" + comment + " Really.",
+		},
+		{
+			name:     "fallback_selects_post",
+			opts:     []extract.Option{extract.WithContentDetection(extract.ContentDetectionFallback)},
+			html:     html,
+			expected: post,
+		},
+		{
+			name:     "scoring_ignores_selectors",
+			opts:     []extract.Option{extract.WithContentDetection(extract.ContentDetectionScoring)},
+			html:     strings.Replace(html, `<div class="wrapper">`, `<div class="wrapper"><main><p>Short teaser line for main.</p></main>`, 1),
+			expected: post,
+		},
+		{
+			name:     "fallback_keeps_matching_selector",
+			opts:     []extract.Option{extract.WithContentDetection(extract.ContentDetectionFallback)},
+			html:     strings.Replace(html, `<div class="wrapper">`, `<div class="wrapper"><main><p>The main element wins when the selectors match it.</p></main>`, 1),
+			expected: titlePrefix + "Blog\n\nThe main element wins when the selectors match it.",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: tc.html}, tc.opts...)
+			assert.NoError(t, err)
+
+			text, hasBody, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/blog/post")
+
+			assert.NoError(t, err)
+			assert.True(t, hasBody)
+			assert.Equal(t, tc.expected, text)
+		})
+	}
+}
+
+func TestFetchAndExtractText_LinkDensity(t *testing.T) {
+	const titlePrefix = "【記事タイトル】 "
+	prose := `This prose paragraph mentions <a href="/docs">the documentation</a> once among many other words.`
+	html := `<html><head><title>Density</title></head><body><article>
+		<p>` + prose + `</p>
+		<ul>
+			<li><a href="/a">Related article one</a></li>
+			<li><a href="/b">Related article two</a> (new)</li>
+			<li>Plain list item</li>
+		</ul>
+		<h2><a href="/section">Linked Heading</a></h2>
+	</article></body></html>`
+	proseText := "This prose paragraph mentions the documentation once among many other words."
+
+	testCases := []struct {
+		name     string
+		opts     []extract.Option
+		expected string
+	}{
+		{
+			name:     "nav_list_is_dropped",
+			expected: titlePrefix + "Density\n\n" + proseText + "\n\nPlain list item\n\n## Linked Heading",
+		},
+		{
+			name: "disabled",
+			opts: []extract.Option{extract.WithMaxLinkDensity(1)},
+			expected: titlePrefix + "Density\n\n" + proseText + "\n\n" +
+				"Related article one\n\nRelated article two (new)\n\nPlain list item\n\n## Linked Heading",
+		},
+		{
+			name:     "stricter_threshold",
+			opts:     []extract.Option{extract.WithMaxLinkDensity(0.1)},
+			expected: titlePrefix + "Density\n\nPlain list item\n\n## Linked Heading",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html}, tc.opts...)
+			assert.NoError(t, err)
+
+			text, hasBody, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/a")
+
+			assert.NoError(t, err)
+			assert.True(t, hasBody)
+			assert.Equal(t, tc.expected, text)
+		})
+	}
+}
+
+func TestFetchAndExtractText_MarkdownLists(t *testing.T) {
+	const titlePrefix = "【記事タイトル】 "
+	html := `<html><head><title>Steps</title></head><body><article>
+		<ol>
+			<li>Install the tool</li>
+			<li>Configure it
+				<ul>
+					<li>Edit the config file</li>
+					<li>Set the token
+						<ol start="3"><li>Open settings</li><li>Copy the token</li></ol>
+					</li>
+				</ul>
+			</li>
+			<li>Run it</li>
+		</ol>
+		<ul><li>Bullet item</li></ul>
+	</article></body></html>`
+
+	t.Run("markdown", func(t *testing.T) {
+		extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html}, extract.WithOutputFormat(extract.FormatMarkdown))
+		assert.NoError(t, err)
+
+		text, _, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/steps")
+
+		assert.NoError(t, err)
+		assert.Equal(t, titlePrefix+"Steps\n\n"+
+			"1. Install the tool\n\n"+
+			"2. Configure it\n\n"+
+			"  - Edit the config file\n\n"+
+			"  - Set the token\n\n"+
+			"    3. Open settings\n\n"+
+			"    4. Copy the token\n\n"+
+			"3. Run it\n\n"+
+			"- Bullet item", text)
+	})
+
+	t.Run("plain_text_has_no_markers", func(t *testing.T) {
+		extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: `<html><head><title>Steps</title></head><body><article>
+			<ol start="5"><li>Install the tool</li><li>Run it</li></ol>
+		</article></body></html>`})
+		assert.NoError(t, err)
+
+		text, _, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/steps")
+
+		assert.NoError(t, err)
+		assert.Equal(t, titlePrefix+"Steps\n\nInstall the tool\n\nRun it", text)
+	})
+}
+
+func TestFetchAndExtractText_NestedLists(t *testing.T) {
+	const titlePrefix = "【記事タイトル】 "
+	html := `<html><head><title>Nested</title></head><body><article>
+		<ul>
+			<li>Fruits
+				<ul>
+					<li>Apple</li>
+					<li>Citrus
+						<ul><li>Orange</li><li>Lemon</li></ul>
+					</li>
+				</ul>
+			</li>
+			<li>Vegetables</li>
+		</ul>
+	</article></body></html>`
+	extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html})
+	assert.NoError(t, err)
+
+	text, _, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/nested")
+
+	assert.NoError(t, err)
+	assert.Equal(t, titlePrefix+"Nested\n\n"+
+		"Fruits\n\n"+
+		"  Apple\n\n"+
+		"  Citrus\n\n"+
+		"    Orange\n\n"+
+		"    Lemon\n\n"+
+		"Vegetables", text)
+	for _, item := range []string{"Apple", "Orange", "Lemon"} {
+		assert.Equal(t, 1, strings.Count(text, item), item)
+	}
+}
+
+func TestFetchAndExtractText_LineBreaks(t *testing.T) {
+	const titlePrefix = "【記事タイトル】 "
+	html := `<html><head><title>Contact</title></head><body><article>
+		<address><p>Example Corporation<br/>
+			1-2-3   Chiyoda,
+			Tokyo<br><br>  Japan</p></address>
+		<p>This paragraph is long enough and is wrapped
+			across source lines without any br.</p>
+	</article></body></html>`
+
+	t.Run("two_lines", func(t *testing.T) {
+		extractor, err := extract.NewExtractor(
+			&MockFetcher{htmlContent: `<html><head><title>Lines</title></head><body><p>line1<br>line2</p></body></html>`},
+			extract.WithFallbackRelaxed(true),
+		)
+		assert.NoError(t, err)
+
+		text, _, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/lines")
+
+		assert.NoError(t, err)
+		assert.Equal(t, titlePrefix+"Lines\n\nline1\nline2", text)
+	})
+
+	t.Run("plain_text", func(t *testing.T) {
+		extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html})
+		assert.NoError(t, err)
+
+		text, _, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/contact")
+
+		assert.NoError(t, err)
+		assert.Equal(t, titlePrefix+"Contact\n\n"+
+			"Example Corporation\n1-2-3 Chiyoda, Tokyo\nJapan\n\n"+
+			"This paragraph is long enough and is wrapped across source lines without any br.", text)
+	})
+
+	t.Run("markdown_with_link", func(t *testing.T) {
+		extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: `<html><head><title>Contact</title></head><body><article>
+			<p>Visit our <a href="/office">head<br>office</a> page<br>or call us during business hours.</p>
+		</article></body></html>`}, extract.WithOutputFormat(extract.FormatMarkdown))
+		assert.NoError(t, err)
+
+		text, _, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/contact")
+
+		assert.NoError(t, err)
+		assert.Equal(t, titlePrefix+"Contact\n\n"+
+			"Visit our [head office](https://example.com/office) page\nor call us during business hours.", text)
+	})
+}
+
+func TestFetchAndExtractText_MarkdownEmphasis(t *testing.T) {
+	const titlePrefix = "【記事タイトル】 "
+	html := `<html><head><title>Emphasis</title></head><body><article>
+		<p>This is <strong>very important</strong> and <em>quite subtle</em>, with <b><i>both</i></b> styles.</p>
+		<p>Read the <a href="/guide"><strong>guide</strong> first</a> and ignore<strong>   </strong>empty markup here.</p>
+		<p>Spacing<strong> around </strong>the emphasis is moved outside of it.</p>
+	</article></body></html>`
+
+	t.Run("markdown", func(t *testing.T) {
+		extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html}, extract.WithOutputFormat(extract.FormatMarkdown))
+		assert.NoError(t, err)
+
+		text, _, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/emphasis")
+
+		assert.NoError(t, err)
+		assert.Equal(t, titlePrefix+"Emphasis\n\n"+
+			"This is **very important** and _quite subtle_, with **_both_** styles.\n\n"+
+			"Read the [**guide** first](https://example.com/guide) and ignore empty markup here.\n\n"+
+			"Spacing **around** the emphasis is moved outside of it.", text)
+	})
+
+	t.Run("plain_text", func(t *testing.T) {
+		extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html})
+		assert.NoError(t, err)
+
+		text, _, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/emphasis")
+
+		assert.NoError(t, err)
+		assert.Equal(t, titlePrefix+"Emphasis\n\n"+
+			"This is very important and quite subtle, with both styles.\n\n"+
+			"Read the guide first and ignore empty markup here.\n\n"+
+			"Spacing around the emphasis is moved outside of it.", text)
+	})
+}
+
+func TestExtractText_EntitiesAndInvisibleChars(t *testing.T) {
+	const titlePrefix = "【記事タイトル】 "
+
+	t.Run("nbsp_and_zero_width_space", func(t *testing.T) {
+		extractor, err := extract.NewExtractor(&MockFetcher{}, extract.WithFallbackRelaxed(true))
+		assert.NoError(t, err)
+
+		text, _, err := extractor.ExtractText(context.Background(),
+			strings.NewReader("<html><head><title>A</title></head><body><p>foo&nbsp;bar\u200bbaz</p></body></html>"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, titlePrefix+"A\n\nfoo bar baz", text)
+	})
+
+	t.Run("double_escaped_entities_and_soft_hyphens", func(t *testing.T) {
+		extractor, err := extract.NewExtractor(&MockFetcher{})
+		assert.NoError(t, err)
+
+		text, _, err := extractor.ExtractText(context.Background(), strings.NewReader(
+			"<html><head><title>A</title></head><body><main>"+
+				"<p>Tom &amp;amp; Jerry&amp;nbsp;are extra&shy;ordinary\ufeff friends\u0007 of 👨‍👩‍👧 families.</p>"+
+				"</main></body></html>"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, titlePrefix+"A\n\nTom & Jerry are extraordinary friends of 👨‍👩‍👧 families.", text)
+	})
+}
+
+func TestFetchAndExtract_ReadingStats(t *testing.T) {
+	html := `<html><head><title>Hello World</title></head><body><main>
+		<p>これは日本語の文です and some English words here.</p>
+		<pre>go test ./...</pre>
+	</main></body></html>`
+
+	testCases := []struct {
+		name            string
+		opts            []extract.Option
+		expectedWords   int
+		expectedSeconds int
+	}{
+		{name: "default_speed", expectedWords: 19, expectedSeconds: 6},
+		{name: "custom_speed", opts: []extract.Option{extract.WithReadingSpeed(60)}, expectedWords: 19, expectedSeconds: 19},
+		{
+			name:            "exclude_code",
+			opts:            []extract.Option{extract.WithReadingSpeed(60), extract.WithExcludeCodeFromReadingStats(true)},
+			expectedWords:   17,
+			expectedSeconds: 17,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html}, tc.opts...)
+			assert.NoError(t, err)
+
+			result, err := extractor.FetchAndExtract(context.Background(), "https://example.com/a")
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedWords, result.WordCount)
+			assert.Equal(t, tc.expectedSeconds, result.ReadingTimeSeconds)
+		})
+	}
+
+	t.Run("exclude_code_keeps_prose_starting_with_backticks", func(t *testing.T) {
+		extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: `<html><head><title>Fences</title></head><body><main>
+			<p>` + "```" + ` marks are used to fence code in Markdown documents.</p>
+			<pre>go test ./...</pre>
+		</main></body></html>`}, extract.WithExcludeCodeFromReadingStats(true))
+		assert.NoError(t, err)
+
+		result, err := extractor.FetchAndExtract(context.Background(), "https://example.com/a")
+
+		assert.NoError(t, err)
+		// タイトルの1語と段落の9語のみを数え、pre 要素のコードは数えない
+		assert.Equal(t, 10, result.WordCount)
+	})
+}
+
+func TestExtractMainImage(t *testing.T) {
+	body := `<header><img src="/logo.png"></header>
+		<article>
+			<img src="https://tracker.example.com/pixel.gif" width="1" height="1">
+			<img src="data:image/gif;base64,R0lGODlhAQABAAAAACw=" data-src="/images/lazy.jpg" width="640">
+			<img src="/images/second.jpg">
+		</article>`
+
+	testCases := []struct {
+		name     string
+		head     string
+		body     string
+		expected string
+	}{
+		{
+			name:     "og_image",
+			head:     `<meta property="og:image" content="/og.png"><meta name="twitter:image" content="/tw.png">`,
+			body:     body,
+			expected: "https://example.com/og.png",
+		},
+		{
+			name:     "twitter_image",
+			head:     `<meta property="og:image" content="data:image/png;base64,AAAA"><meta name="twitter:image" content="//cdn.example.com/tw.png">`,
+			body:     body,
+			expected: "https://cdn.example.com/tw.png",
+		},
+		{
+			name:     "first_large_image_in_main_content",
+			body:     body,
+			expected: "https://example.com/images/lazy.jpg",
+		},
+		{
+			name:     "no_suitable_image",
+			body:     `<article><img src="/icon.png" width="16" height="16"><p>No images here.</p></article>`,
+			expected: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			html := `<html><head><title>Image</title>` + tc.head + `</head><body>` + tc.body + `</body></html>`
+			extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html})
+			assert.NoError(t, err)
+
+			image, err := extractor.ExtractMainImage(context.Background(), "https://example.com/posts/1")
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, image)
+		})
+	}
+}
+
+func TestFetchAndExtract_PublishedAtAndAuthor(t *testing.T) {
+	body := `<p>This paragraph is long enough to be treated as extracted article body.</p>`
+
+	testCases := []struct {
+		name              string
+		head              string
+		body              string
+		expectedPublished time.Time
+		expectedAuthor    string
+	}{
+		{
+			name: "meta_tags",
+			head: `<meta property="article:published_time" content="2024-05-01T09:00:00+09:00">
+				<meta name="author" content="Taro Yamada">`,
+			body:              `<article>` + body + `<time datetime="2020-01-01">old</time></article>`,
+			expectedPublished: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+			expectedAuthor:    "Taro Yamada",
+		},
+		{
+			name:              "time_element_and_rel_author",
+			body:              `<article><p>By <a rel="author" href="/hanako">Hanako  Suzuki</a></p><time datetime="2024-06-01T10:30:00Z">June 1</time>` + body + `</article>`,
+			expectedPublished: time.Date(2024, 6, 1, 10, 30, 0, 0, time.UTC),
+			expectedAuthor:    "Hanako Suzuki",
+		},
+		{
+			name: "json_ld",
+			head: `<script type="application/ld+json">{"@type":"NewsArticle","datePublished":"2024-07-15",
+				"author":[{"@type":"Person","name":"Alice"},{"@type":"Person","name":"Bob"}]}</script>`,
+			body:              `<article>` + body + `</article>`,
+			expectedPublished: time.Date(2024, 7, 15, 0, 0, 0, 0, time.UTC),
+			expectedAuthor:    "Alice, Bob",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			html := `<html><head><title>A</title>` + tc.head + `</head><body>` + tc.body + `</body></html>`
+			extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html})
+			assert.NoError(t, err)
+
+			result, err := extractor.FetchAndExtract(context.Background(), "https://example.com/a")
+
+			assert.NoError(t, err)
+			if assert.NotNil(t, result.PublishedAt) {
+				assert.True(t, result.PublishedAt.Equal(tc.expectedPublished), result.PublishedAt.String())
+			}
+			assert.Equal(t, tc.expectedAuthor, result.Author)
+		})
+	}
+
+	t.Run("absent", func(t *testing.T) {
+		html := `<html><head><title>A</title><meta property="article:published_time" content="yesterday"></head>
+			<body><article>` + body + `</article></body></html>`
+		extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html})
+		assert.NoError(t, err)
+
+		result, err := extractor.FetchAndExtract(context.Background(), "https://example.com/a")
+
+		assert.NoError(t, err)
+		assert.Nil(t, result.PublishedAt)
+		assert.Empty(t, result.Author)
+	})
+}
+
+func TestFetchAndExtract_JSONLDArticle(t *testing.T) {
+	body := `<article><p>This paragraph is long enough to be treated as extracted article body.</p></article>`
+
+	t.Run("single_object", func(t *testing.T) {
+		html := `<html><head><title>Breaking: Launch | Example News</title>
+			<script type="application/ld+json">{"@context":"https://schema.org","@type":"NewsArticle",
+				"headline":"Launch  succeeds","description":"Rocket reached orbit.",
+				"datePublished":"2024-05-01T09:00:00+09:00","author":{"@type":"Person","name":"Taro"}}</script>
+		</head><body>` + body + `</body></html>`
+		extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html},
+			extract.WithTitleSource([]string{extract.TitleSourceJSONLD, extract.TitleSourceTitle}))
+		assert.NoError(t, err)
+
+		result, err := extractor.FetchAndExtract(context.Background(), "https://example.com/a")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Launch succeeds", result.Title)
+		if assert.NotNil(t, result.Article) {
+			assert.Equal(t, "NewsArticle", result.Article.Type)
+			assert.Equal(t, "Launch succeeds", result.Article.Headline)
+			assert.Equal(t, "Rocket reached orbit.", result.Article.Description)
+			assert.Equal(t, "Taro", result.Article.Author)
+			if assert.NotNil(t, result.Article.DatePublished) {
+				assert.True(t, result.Article.DatePublished.Equal(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)))
+			}
+		}
+	})
+
+	t.Run("array_and_malformed_scripts", func(t *testing.T) {
+		html := `<html><head><title>A</title>
+			<script type="application/ld+json">{"@type": "BlogPosting", broken</script>
+			<script type="application/ld+json">[{"@type":"Organization","name":"Example"},
+				{"@type":["BlogPosting"],"headline":"Post","author":"Hanako"}]</script>
+		</head><body>` + body + `</body></html>`
+		extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html})
+		assert.NoError(t, err)
+
+		result, err := extractor.FetchAndExtract(context.Background(), "https://example.com/a")
+
+		assert.NoError(t, err)
+		assert.Equal(t, &extract.JSONLDArticle{Type: "BlogPosting", Headline: "Post", Author: "Hanako"}, result.Article)
+	})
+
+	t.Run("no_article", func(t *testing.T) {
+		html := `<html><head><title>A</title>
+			<script type="application/ld+json">{"@type":"Recipe","name":"Curry"}</script>
+		</head><body>` + body + `</body></html>`
+		extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html})
+		assert.NoError(t, err)
+
+		result, err := extractor.FetchAndExtract(context.Background(), "https://example.com/a")
+
+		assert.NoError(t, err)
+		assert.Nil(t, result.Article)
+	})
+}
+
+func TestFetchAndExtractText_Prefixes(t *testing.T) {
+	html := `<html><head><title>Prefix Test</title></head><body><article>
+		<table><caption>Data Table</caption><tr><td>Col1</td><td>Val1</td></tr></table>
+	</article></body></html>`
+
+	tests := []struct {
+		name     string
+		opts     []extract.Option
+		expected string
+	}{
+		{
+			name:     "default",
+			expected: "【記事タイトル】 Prefix Test\n\n【表題】 Data Table\nCol1 | Val1",
+		},
+		{
+			name:     "custom",
+			opts:     []extract.Option{extract.WithTitlePrefix("Title: "), extract.WithTableCaptionPrefix("Table: ")},
+			expected: "Title: Prefix Test\n\nTable: Data Table\nCol1 | Val1",
+		},
+		{
+			name:     "suppressed",
+			opts:     []extract.Option{extract.WithTitlePrefix(""), extract.WithTableCaptionPrefix("")},
+			expected: "Prefix Test\n\nData Table\nCol1 | Val1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html}, tt.opts...)
+			assert.NoError(t, err)
+
+			text, hasBody, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/prefix")
+
+			assert.NoError(t, err)
+			assert.True(t, hasBody)
+			assert.Equal(t, tt.expected, text)
+		})
+	}
+
+	t.Run("title_only_without_prefix", func(t *testing.T) {
+		extractor, err := extract.NewExtractor(
+			&MockFetcher{htmlContent: `<html><head><title>Only Title</title></head><body></body></html>`},
+			extract.WithTitlePrefix(""),
+		)
+		assert.NoError(t, err)
+
+		text, hasBody, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/title-only")
+
+		assert.NoError(t, err)
+		assert.False(t, hasBody)
+		assert.Equal(t, "Only Title", text)
+	})
+}
+
+func TestFetchAndExtractText_BodyStartingWithTitlePrefix(t *testing.T) {
+	const titlePrefix = "【記事タイトル】 "
+	body := titlePrefix + "という見出しの付け方について、本文の最初の段落で詳しく解説します。"
+	html := `<html><head></head><body><article><p>` + body + `</p></article></body></html>`
+
+	extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html})
+	assert.NoError(t, err)
+
+	text, hasBody, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/no-title")
+
+	assert.NoError(t, err)
+	assert.True(t, hasBody, "タイトルの接頭辞で始まる本文の段落をタイトルとして扱わないこと")
+	assert.Equal(t, body, text)
+}
+
+func TestFetchAndExtractText_ErrNoContent(t *testing.T) {
+	extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: `<html><head><title></title></head><body></body></html>`})
+	assert.NoError(t, err)
+
+	_, _, err = extractor.FetchAndExtractText(context.Background(), "https://example.com/empty")
+	assert.ErrorIs(t, err, extract.ErrNoContent)
+	assert.ErrorIs(t, err, ports.ErrNoContent)
+	assert.EqualError(t, err, "webページから何も抽出できませんでした")
+
+	_, err = extractor.FetchAndExtract(context.Background(), "https://example.com/empty")
+	assert.ErrorIs(t, err, extract.ErrNoContent)
+
+	t.Run("fetch_error_is_not_no_content", func(t *testing.T) {
+		extractor, err := extract.NewExtractor(&MockFetcher{fetchError: errors.New("network error")})
+		assert.NoError(t, err)
+
+		_, _, err = extractor.FetchAndExtractText(context.Background(), "https://example.com/down")
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, extract.ErrNoContent)
+	})
+}
+
+func TestFetchAndExtractText_AllowTitleOnly(t *testing.T) {
+	const titlePrefix = "【記事タイトル】 "
+
+	tests := []struct {
+		name          string
+		html          string
+		opts          []extract.Option
+		expectedText  string
+		expectedBody  bool
+		expectedError error
+	}{
+		{
+			name:         "default_title_only_has_no_body",
+			html:         `<html><head><title>Only Title</title></head><body></body></html>`,
+			expectedText: titlePrefix + "Only Title",
+			expectedBody: false,
+		},
+		{
+			name:         "title_only_allowed",
+			html:         `<html><head><title>Only Title</title></head><body></body></html>`,
+			opts:         []extract.Option{extract.WithAllowTitleOnly(true)},
+			expectedText: titlePrefix + "Only Title",
+			expectedBody: true,
+		},
+		{
+			name:          "nothing_extracted_is_still_an_error",
+			html:          `<html><head><title></title></head><body></body></html>`,
+			opts:          []extract.Option{extract.WithAllowTitleOnly(true)},
+			expectedError: extract.ErrNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: tt.html}, tt.opts...)
+			assert.NoError(t, err)
+
+			text, hasBody, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/title")
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedBody, hasBody)
+			assert.Equal(t, tt.expectedText, text)
+		})
+	}
+}
+
+func TestExtractFromBytes(t *testing.T) {
+	const titlePrefix = "【記事タイトル】 "
+	html := []byte(`<html><head><title>Cached Page</title></head><body><article>
+		<p>This cached paragraph links to <a href="/guide">the guide</a> for more details.</p>
+	</article></body></html>`)
+
+	// ExtractFromBytes は Fetcher を呼び出さない
+	extractor, err := extract.NewExtractor(&MockFetcher{fetchError: errors.New("fetch must not be called")},
+		extract.WithOutputFormat(extract.FormatMarkdown))
+	assert.NoError(t, err)
+
+	text, hasBody, err := extractor.ExtractFromBytes(context.Background(), html, "https://example.com/docs/page")
+
+	assert.NoError(t, err)
+	assert.True(t, hasBody)
+	assert.Equal(t, titlePrefix+"Cached Page\n\n"+
+		"This cached paragraph links to [the guide](https://example.com/guide) for more details.", text)
+
+	t.Run("reader_matches_bytes", func(t *testing.T) {
+		fromReader, readerHasBody, err := extractor.ExtractFromReader(context.Background(), strings.NewReader(string(html)), "https://example.com/docs/page")
+
+		assert.NoError(t, err)
+		assert.Equal(t, hasBody, readerHasBody)
+		assert.Equal(t, text, fromReader)
+	})
+}
+
+// cancelAfterContext は、Err が指定回数呼び出された後に context.Canceled を返す Context です。
+// 解析の途中でキャンセルされた状況を決定的に再現するために使用します。
+type cancelAfterContext struct {
+	context.Context
+	remaining int
+}
+
+func (c *cancelAfterContext) Err() error {
+	if c.remaining <= 0 {
+		return context.Canceled
+	}
+	c.remaining--
+	return nil
+}
+
+func TestExtractText_CancelDuringCollection(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("<html><head><title>Huge</title></head><body><article>")
+	for i := 0; i < 2000; i++ {
+		fmt.Fprintf(&sb, "<p>Paragraph number %d is long enough to be kept as body text.</p>", i)
+	}
+	sb.WriteString("</article></body></html>")
+
+	extractor, err := extract.NewExtractor(&MockFetcher{})
+	assert.NoError(t, err)
+
+	// 解析前の確認と最初の要素での確認のみ通過させ、収集の途中でキャンセルします
+	ctx := &cancelAfterContext{Context: context.Background(), remaining: 2}
+	text, hasBody, err := extractor.ExtractText(ctx, strings.NewReader(sb.String()))
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, hasBody)
+	assert.Empty(t, text)
+}
+
+func TestFetchAndExtractText_InterleavedDocumentOrder(t *testing.T) {
+	const titlePrefix = "【記事タイトル】 "
+	html := `<html><head><title>Interleaved</title></head><body><article>
+		<p>First, install the command line tool with the package manager.</p>
+		<pre>go install example.com/tool@latest</pre>
+		<p>The supported platforms are listed in the following table.</p>
+		<table><tr><td>linux</td><td>amd64</td></tr></table>
+		<pre>tool --version</pre>
+		<p>Finally, check that the version matches the release notes.</p>
+	</article></body></html>`
+
+	extractor, err := extract.NewExtractor(&MockFetcher{htmlContent: html})
+	assert.NoError(t, err)
+
+	text, _, err := extractor.FetchAndExtractText(context.Background(), "https://example.com/install")
+
+	assert.NoError(t, err)
+	// 段落・コードブロック・表は種類ごとにまとめず、DOMの出現順に出力されること
+	assert.Equal(t, titlePrefix+"Interleaved\n\n"+
+		"First, install the command line tool with the package manager.\n\n"+
+		"```\ngo install example.com/tool@latest\n```\n\n"+
+		"The supported platforms are listed in the following table.\n\n"+
+		"linux | amd64\n\n"+
+		"```\ntool --version\n```\n\n"+
+		"Finally, check that the version matches the release notes.", text)
+}
@@ -0,0 +1,116 @@
+package extract
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// OutputFormat は抽出テキストの出力形式を表します。
+type OutputFormat int
+
+const (
+	// FormatPlainText は従来のプレーンテキスト形式です (デフォルト)。
+	FormatPlainText OutputFormat = iota
+	// FormatMarkdown は見出しレベルなどの構造を保持した Markdown 形式です。
+	FormatMarkdown
+)
+
+// frontmatterFields は frontmatter に出力するメタデータを保持します。
+type frontmatterFields struct {
+	title     string
+	url       string
+	author    string
+	published string
+	language  string
+}
+
+// readFrontmatterFields はドキュメントから frontmatter 用のメタデータを読み取ります。
+// 著者と公開日時は ExtractionResult の Author / PublishedAt と同じ方法で取得し、公開日時は RFC 3339 形式で出力します。
+func (e *Extractor) readFrontmatterFields(doc *goquery.Document, pageURL string) frontmatterFields {
+	jsonLD := jsonLDObjects(doc)
+	fields := frontmatterFields{
+		title:    e.findTitle(doc),
+		url:      strings.TrimSpace(pageURL),
+		author:   findAuthor(doc, jsonLD),
+		language: strings.TrimSpace(doc.Find("html").First().AttrOr("lang", "")),
+	}
+	if published := findPublishedAt(doc, jsonLD); published != nil {
+		fields.published = published.Format(time.RFC3339)
+	}
+	return fields
+}
+
+// renderFrontmatter は YAML frontmatter ブロックを生成します。
+// 値が空のフィールドは出力せず、すべて空の場合は空文字列を返します。
+func renderFrontmatter(f frontmatterFields) string {
+	entries := []struct{ key, value string }{
+		{"title", f.title},
+		{"url", f.url},
+		{"author", f.author},
+		{"published", f.published},
+		{"language", f.language},
+	}
+
+	var lines []string
+	for _, entry := range entries {
+		if entry.value != "" {
+			// YAML のダブルクォート文字列として安全にエスケープします
+			lines = append(lines, entry.key+": "+strconv.Quote(entry.value))
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return "---\n" + strings.Join(lines, "\n") + "\n---"
+}
+
+// headingPrefix は見出しタグ名に対応する見出し記号を返します。
+// Markdown 形式では見出しレベルを保持し、それ以外では従来どおり "## " を返します。
+func (e *Extractor) headingPrefix(tagName string) string {
+	if e.outputFormat == FormatMarkdown {
+		if level, err := strconv.Atoi(strings.TrimPrefix(tagName, "h")); err == nil && level >= 1 && level <= 6 {
+			return strings.Repeat("#", level) + " "
+		}
+	}
+	return "## "
+}
+
+// listItemPrefix は、リスト項目 (li) の先頭に付ける字下げと記号を返します。
+// 入れ子のリストは階層ごとに空白2つで字下げします。Markdown 形式では、ol の項目には
+// start 属性を考慮した "1. " のような番号を、それ以外には "- " を付けます。
+func (e *Extractor) listItemPrefix(node *html.Node) string {
+	if node == nil {
+		return ""
+	}
+
+	depth := 0
+	for ancestor := node.Parent; ancestor != nil; ancestor = ancestor.Parent {
+		if ancestor.Type == html.ElementNode && (ancestor.Data == "ul" || ancestor.Data == "ol") {
+			depth++
+		}
+	}
+	indent := strings.Repeat("  ", max(depth-1, 0))
+	if e.outputFormat != FormatMarkdown {
+		return indent
+	}
+
+	list := node.Parent
+	if list == nil || list.Type != html.ElementNode || list.Data != "ol" {
+		return indent + "- "
+	}
+
+	number := 1
+	if start, err := strconv.Atoi(strings.TrimSpace(attrValue(list, "start"))); err == nil {
+		number = start
+	}
+	for sib := node.PrevSibling; sib != nil; sib = sib.PrevSibling {
+		if sib.Type == html.ElementNode && sib.Data == "li" {
+			number++
+		}
+	}
+	return indent + strconv.Itoa(number) + ". "
+}
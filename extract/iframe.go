@@ -0,0 +1,50 @@
+package extract
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// findSameOriginIframe は、ページと同一オリジン (スキーム・ホストが一致) の
+// 最初の iframe の絶対URLを返します。該当が無い場合は空文字列を返します。
+// 第三者の埋め込みコンテンツを取得しないよう、別オリジンの iframe は対象外です。
+func findSameOriginIframe(doc *goquery.Document, pageURL string) string {
+	base := parseBaseURL(pageURL)
+	if base == nil {
+		return ""
+	}
+
+	// src は base 要素を考慮して解決し、オリジンの比較はページURLに対して行います
+	srcBase := documentBaseURL(doc, pageURL)
+	var frameURL string
+	doc.Find("iframe[src]").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		resolved := parseBaseURL(resolveURL(srcBase, s.AttrOr("src", "")))
+		if resolved == nil || resolved.Scheme != base.Scheme || !strings.EqualFold(resolved.Host, base.Host) {
+			return true
+		}
+		if resolved.String() == base.String() {
+			return true
+		}
+		frameURL = resolved.String()
+		return false
+	})
+	return frameURL
+}
+
+// fetchIframeDocument は iframe のURLからHTMLを取得して解析します。
+// iframe の取得・解析に失敗した場合は元ページの結果を優先するため、エラーは返しません。
+// ループを防ぐため、取得した iframe 内の iframe はさらに辿りません。
+func (e *Extractor) fetchIframeDocument(ctx context.Context, frameURL string) (*goquery.Document, bool) {
+	htmlBytes, err := e.fetcher.FetchBytes(ctx, frameURL)
+	if err != nil {
+		return nil, false
+	}
+	doc, err := e.parseDocument(ctx, bytes.NewReader(htmlBytes), "")
+	if err != nil {
+		return nil, false
+	}
+	return doc, true
+}
@@ -0,0 +1,79 @@
+package extract
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// minMainImageSize は、本文中の画像を代表画像として採用する幅・高さの最小値 (ピクセル) です。
+// width / height 属性がこれに満たない画像はアイコンやトラッキングピクセルとみなします。
+const minMainImageSize = 100
+
+// mainImageMetaKeys は、代表画像を宣言する meta 要素のキーを優先順に並べたものです。
+var mainImageMetaKeys = []string{"og:image", "twitter:image", "twitter:image:src"}
+
+// ExtractMainImage は指定されたURLからコンテンツを取得し、ページの代表画像の絶対URLを返します。
+// og:image、twitter:image の順に参照し、宣言が無い場合はメインコンテンツ内の最初の十分な大きさの
+// img 要素を採用します。data: URI とトラッキングピクセルは対象外で、
+// 適切な画像が無い場合はエラーではなく空文字列を返します。
+func (e *Extractor) ExtractMainImage(ctx context.Context, url string) (string, error) {
+	htmlBytes, err := e.fetcher.FetchBytes(ctx, url)
+	if err != nil {
+		return "", err
+	}
+
+	doc, err := e.parseDocument(ctx, bytes.NewReader(htmlBytes), "")
+	if err != nil {
+		return "", err
+	}
+	return e.findMainImage(doc, url), nil
+}
+
+// findMainImage は meta 要素、メインコンテンツ内の img 要素の順に代表画像を探します。
+func (e *Extractor) findMainImage(doc *goquery.Document, pageURL string) string {
+	base := documentBaseURL(doc, pageURL)
+
+	for _, key := range mainImageMetaKeys {
+		if image := resolveURL(base, metaContent(doc, key)); isUsableImageURL(image) {
+			return image
+		}
+	}
+
+	var image string
+	e.findMainContent(doc).Find("img").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		if isTinyImage(s) {
+			return true
+		}
+		// 遅延読み込みの画像は src にプレースホルダーを置き、data-src に実際のURLを持ちます
+		for _, attr := range []string{"src", "data-src"} {
+			if candidate := resolveURL(base, s.AttrOr(attr, "")); isUsableImageURL(candidate) {
+				image = candidate
+				return false
+			}
+		}
+		return true
+	})
+	return image
+}
+
+// isUsableImageURL は、URL が代表画像として使える http(s) の絶対URLであるかを判定します。
+func isUsableImageURL(image string) bool {
+	lower := strings.ToLower(image)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
+
+// isTinyImage は、width または height 属性が minMainImageSize 未満の img 要素であるかを判定します。
+// 属性が無い、または数値として解釈できない場合は大きさを判断せず false を返します。
+func isTinyImage(s *goquery.Selection) bool {
+	for _, attr := range []string{"width", "height"} {
+		value := strings.TrimSuffix(strings.TrimSpace(s.AttrOr(attr, "")), "px")
+		if n, err := strconv.Atoi(value); err == nil && n < minMainImageSize {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package extract
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// inlineStateAssignment は、初期データをグローバル変数へ代入する代表的なパターンです。
+// 例: window.__INITIAL_STATE__ = {...}
+var inlineStateAssignment = regexp.MustCompile(`window\.(__[A-Z0-9_]+__)\s*=\s*`)
+
+// findInlineJSON はスクリプト要素に埋め込まれたJSON文字列を収集します。
+// type="application/json" のスクリプト (例: __NEXT_DATA__) と、
+// window.__XXX__ = {...} 形式の代入を対象とし、JSONとして不正なものは無視します。
+func findInlineJSON(doc *goquery.Document) []string {
+	var blobs []string
+	doc.Find("script").Each(func(i int, s *goquery.Selection) {
+		body := strings.TrimSpace(s.Text())
+		if body == "" {
+			return
+		}
+
+		scriptType := strings.ToLower(strings.TrimSpace(s.AttrOr("type", "")))
+		if scriptType == "application/json" {
+			if json.Valid([]byte(body)) {
+				blobs = append(blobs, body)
+			}
+			return
+		}
+		if scriptType != "" && scriptType != "text/javascript" && scriptType != "application/javascript" {
+			return
+		}
+
+		for _, loc := range inlineStateAssignment.FindAllStringIndex(body, -1) {
+			if blob := scanJSONValue(body[loc[1]:]); blob != "" && json.Valid([]byte(blob)) {
+				blobs = append(blobs, blob)
+			}
+		}
+	})
+	return blobs
+}
+
+// scanJSONValue は s の先頭から始まるJSONオブジェクトまたは配列を、
+// 括弧の対応と文字列リテラルを考慮して切り出します。
+func scanJSONValue(s string) string {
+	if s == "" || (s[0] != '{' && s[0] != '[') {
+		return ""
+	}
+
+	depth := 0
+	inString := false
+	escaped := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+		case '}', ']':
+			depth--
+			if depth == 0 {
+				return s[:i+1]
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,153 @@
+package extract
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// markdownLinkTextEscaper は、リンクテキスト中の Markdown のリンク構文と衝突する文字をエスケープします。
+var markdownLinkTextEscaper = strings.NewReplacer("[", `\[`, "]", `\]`)
+
+// markdownLinkURLEscaper は、リンク先URL中のリンク構文を閉じてしまう括弧をエスケープします。
+var markdownLinkURLEscaper = strings.NewReplacer("(", "%28", ")", "%29", " ", "%20")
+
+// hasLinkDescendant は、node の子孫に href を持つ a 要素が含まれるかを判定します。
+// pre と table 要素は別のパーツとして出力されるため、その内部は対象外です。
+func hasLinkDescendant(node *html.Node) bool {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode || isSeparatePart(child, false) {
+			continue
+		}
+		if child.Data == "a" && hasAttr(child, "href") || hasLinkDescendant(child) {
+			return true
+		}
+	}
+	return false
+}
+
+// markdownEmphasisDelimiters は、Markdown で強調として出力する要素と区切り記号です。
+var markdownEmphasisDelimiters = map[string]string{
+	"strong": "**",
+	"b":      "**",
+	"em":     "_",
+	"i":      "_",
+}
+
+// hasInlineMarkdownDescendant は、node の子孫に Markdown のインライン記法で出力する要素
+// (href を持つ a 要素と strong, b, em, i 要素) が含まれるかを判定します。
+func hasInlineMarkdownDescendant(node *html.Node) bool {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode || isSeparatePart(child, false) {
+			continue
+		}
+		if child.Data == "a" && hasAttr(child, "href") || markdownEmphasisDelimiters[child.Data] != "" {
+			return true
+		}
+		if hasInlineMarkdownDescendant(child) {
+			return true
+		}
+	}
+	return false
+}
+
+// markdownInlineText は、子孫の a 要素を Markdown のインラインリンク [text](url) に、
+// strong と b 要素を **text** に、em と i 要素を _text_ に変換した node のテキストを返します。
+// href は base に対して絶対URLに解決します。
+// 解決できない href や javascript:、mailto: のリンクは従来どおりテキストのみを出力します。
+// nestedLists が true の場合は入れ子のリスト (ul, ol) の内容を含めません。
+func markdownInlineText(node *html.Node, base *url.URL, nestedLists bool) string {
+	var builder strings.Builder
+	writeMarkdownInlineText(&builder, node, base, nestedLists)
+	return builder.String()
+}
+
+// writeMarkdownInlineText は node の子孫のテキストを出現順に書き込みます。
+// writeTextExcludingSeparateParts と同様に pre と table 要素の内容はスキップし、br 要素は改行の目印とします。
+func writeMarkdownInlineText(builder *strings.Builder, node *html.Node, base *url.URL, nestedLists bool) {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		switch child.Type {
+		case html.TextNode:
+			builder.WriteString(child.Data)
+		case html.ElementNode:
+			if child.Data == "br" {
+				builder.WriteString(lineBreakMarker)
+				continue
+			}
+			if isSeparatePart(child, nestedLists) {
+				continue
+			}
+			if child.Data == "a" {
+				writeMarkdownLink(builder, child, base)
+				continue
+			}
+			if delimiter := markdownEmphasisDelimiters[child.Data]; delimiter != "" {
+				writeMarkdownEmphasis(builder, child, base, nestedLists, delimiter)
+				continue
+			}
+			writeMarkdownInlineText(builder, child, base, nestedLists)
+		}
+	}
+}
+
+// writeMarkdownEmphasis は強調要素の内容を delimiter で囲んで書き込みます。
+// Markdown では区切り記号の内側に空白を置けないため、前後の空白は区切り記号の外側に出します。
+// 空白のみの強調は区切り記号を付けずにそのまま書き込みます。
+func writeMarkdownEmphasis(builder *strings.Builder, node *html.Node, base *url.URL, nestedLists bool, delimiter string) {
+	inner := markdownInlineText(node, base, nestedLists)
+	trimmed := strings.TrimSpace(inner)
+	if trimmed == "" {
+		builder.WriteString(inner)
+		return
+	}
+
+	start := strings.Index(inner, trimmed)
+	builder.WriteString(inner[:start])
+	builder.WriteString(delimiter)
+	builder.WriteString(trimmed)
+	builder.WriteString(delimiter)
+	builder.WriteString(inner[start+len(trimmed):])
+}
+
+// writeMarkdownLink は a 要素を [text](url) として書き込みます。
+// リンク内の strong などの強調はリンクテキストの中で保持します。
+func writeMarkdownLink(builder *strings.Builder, node *html.Node, base *url.URL) {
+	linkText := collapseSpaces(markdownInlineText(node, base, false))
+
+	href := strings.TrimSpace(attrValue(node, "href"))
+	var absolute string
+	if href != "" && !hasSkippedLinkScheme(href) {
+		absolute = resolveURL(base, href)
+	}
+	if linkText == "" || absolute == "" {
+		builder.WriteString(linkText)
+		return
+	}
+
+	builder.WriteString("[")
+	builder.WriteString(markdownLinkTextEscaper.Replace(linkText))
+	builder.WriteString("](")
+	builder.WriteString(markdownLinkURLEscaper.Replace(absolute))
+	builder.WriteString(")")
+}
+
+// hasAttr は node が key 属性を持つかを判定します。
+func hasAttr(node *html.Node, key string) bool {
+	for _, attr := range node.Attr {
+		if attr.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// attrValue は node の key 属性の値を返します。属性が無い場合は空文字列を返します。
+func attrValue(node *html.Node, key string) string {
+	for _, attr := range node.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
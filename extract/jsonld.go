@@ -0,0 +1,97 @@
+package extract
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// jsonLDObjects は application/ld+json スクリプトに含まれるオブジェクトを出現順に返します。
+// トップレベルの配列や @graph に含まれるオブジェクトも展開します。解析できないスクリプトは無視します。
+func jsonLDObjects(doc *goquery.Document) []map[string]any {
+	var objects []map[string]any
+	doc.Find(`script[type="application/ld+json"]`).Each(func(i int, s *goquery.Selection) {
+		var value any
+		if err := json.Unmarshal([]byte(strings.TrimSpace(s.Text())), &value); err != nil {
+			return
+		}
+		objects = appendJSONLDObjects(objects, value)
+	})
+	return objects
+}
+
+// appendJSONLDObjects は value に含まれるオブジェクトを再帰的に objects へ追加します。
+func appendJSONLDObjects(objects []map[string]any, value any) []map[string]any {
+	switch v := value.(type) {
+	case []any:
+		for _, item := range v {
+			objects = appendJSONLDObjects(objects, item)
+		}
+	case map[string]any:
+		objects = append(objects, v)
+		if graph, ok := v["@graph"]; ok {
+			objects = appendJSONLDObjects(objects, graph)
+		}
+	}
+	return objects
+}
+
+// hasJSONLDType は、オブジェクトの @type が typeName を含むかを判定します。
+// @type は文字列または文字列の配列のどちらでも構いません。
+func hasJSONLDType(object map[string]any, typeName string) bool {
+	switch t := object["@type"].(type) {
+	case string:
+		return t == typeName
+	case []any:
+		for _, item := range t {
+			if s, ok := item.(string); ok && s == typeName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jsonLDArticleTypes は、記事として扱う schema.org の @type です。
+var jsonLDArticleTypes = []string{"Article", "NewsArticle", "BlogPosting"}
+
+// JSONLDArticle は、JSON-LD (schema.org) で宣言された記事のメタデータです。
+type JSONLDArticle struct {
+	Type          string     // @type (例: "NewsArticle")
+	Headline      string     // headline
+	Description   string     // description
+	DatePublished *time.Time // datePublished。解析できない場合は nil
+	Author        string     // author の名前。複数の場合は ", " で連結します
+}
+
+// findJSONLDArticle は、@type が Article、NewsArticle、BlogPosting のいずれかである
+// 最初の JSON-LD オブジェクトを jsonLDObjects で解析済みの jsonLD から探して返します。
+// 該当するオブジェクトが無い場合は nil を返します。
+func findJSONLDArticle(jsonLD []map[string]any) *JSONLDArticle {
+	for _, object := range jsonLD {
+		for _, typeName := range jsonLDArticleTypes {
+			if !hasJSONLDType(object, typeName) {
+				continue
+			}
+			article := &JSONLDArticle{
+				Type:        typeName,
+				Headline:    jsonLDString(object, "headline"),
+				Description: jsonLDString(object, "description"),
+				Author:      strings.Join(jsonLDNames(object["author"]), ", "),
+			}
+			if t, ok := parseDateTime(jsonLDString(object, "datePublished")); ok {
+				article.DatePublished = &t
+			}
+			return article
+		}
+	}
+	return nil
+}
+
+// jsonLDString は、オブジェクトの key の値が文字列の場合に空白を正規化して返します。
+func jsonLDString(object map[string]any, key string) string {
+	value, _ := object[key].(string)
+	return collapseSpaces(value)
+}
@@ -0,0 +1,75 @@
+package extract
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Link は、本文中のリンク (a 要素) の情報です。
+type Link struct {
+	AbsoluteURL string // href をページURL (base 要素があればその値) に対して解決した絶対URL
+	AnchorText  string // 空白を正規化したリンクテキスト
+	Rel         string // rel 属性の値 (例: "nofollow")。宣言が無い場合は空文字列です。
+}
+
+// skippedLinkSchemes はリンクとして収集しない href のスキームです。
+var skippedLinkSchemes = []string{"javascript:", "mailto:"}
+
+// ExtractLinks は指定されたURLからコンテンツを取得し、メインコンテンツ内のリンクを出現順に返します。
+// 空の href や javascript:、mailto: のリンクは除外し、同じ絶対URLへのリンクは最初のもののみを返します。
+// ページのナビゲーションやサイドバーなど、本文抽出で対象外となる部分のリンクは含みません。
+func (e *Extractor) ExtractLinks(ctx context.Context, url string) ([]Link, error) {
+	htmlBytes, err := e.fetcher.FetchBytes(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := e.parseDocument(ctx, bytes.NewReader(htmlBytes), "")
+	if err != nil {
+		return nil, err
+	}
+	return e.findLinks(doc, url), nil
+}
+
+// findLinks は findMainContent で特定したメインコンテンツから a 要素を収集します。
+func (e *Extractor) findLinks(doc *goquery.Document, pageURL string) []Link {
+	// base 要素は head にあるため、メインコンテンツの特定より前に解決します
+	base := documentBaseURL(doc, pageURL)
+
+	mainContent := e.findMainContent(doc)
+	mainContent.Find(e.noiseSelector()).Remove()
+
+	var links []Link
+	seen := make(map[string]bool)
+	mainContent.Find("a[href]").Each(func(i int, s *goquery.Selection) {
+		href := strings.TrimSpace(s.AttrOr("href", ""))
+		if href == "" || hasSkippedLinkScheme(href) {
+			return
+		}
+		absolute := resolveURL(base, href)
+		if absolute == "" || seen[absolute] {
+			return
+		}
+		seen[absolute] = true
+		links = append(links, Link{
+			AbsoluteURL: absolute,
+			AnchorText:  collapseSpaces(s.Text()),
+			Rel:         strings.TrimSpace(s.AttrOr("rel", "")),
+		})
+	})
+	return links
+}
+
+// hasSkippedLinkScheme は href が収集対象外のスキームで始まるかを判定します。
+func hasSkippedLinkScheme(href string) bool {
+	lower := strings.ToLower(href)
+	for _, scheme := range skippedLinkSchemes {
+		if strings.HasPrefix(lower, scheme) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package extract
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"sync"
+)
+
+// Content は ExtractMany における1ドキュメント分の抽出結果です。
+type Content struct {
+	Text    string // 整形済みのテキスト (ExtractText と同じ形式)
+	HasBody bool   // 本文が検出された場合は true
+	Error   error  // 抽出中に発生したエラー
+}
+
+// ExtractMany は、取得済みの複数のHTMLドキュメントを並列に解析して本文を抽出します。
+// goquery による解析は CPU 負荷が高いため、I/O 待ちが中心のスクレイパーとは別に
+// WithConcurrentExtraction で指定した数 (デフォルトは GOMAXPROCS) のワーカーで処理します。
+// 結果は入力と同じ順序で返されます。Context が終了した後に未処理のドキュメントにはそのエラーが設定されます。
+func (e *Extractor) ExtractMany(ctx context.Context, docs [][]byte) []Content {
+	results := make([]Content, len(docs))
+
+	workers := e.extractWorkers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, len(docs))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if err := ctx.Err(); err != nil {
+					results[i].Error = err
+					continue
+				}
+				text, hasBody, err := e.ExtractText(ctx, bytes.NewReader(docs[i]))
+				results[i] = Content{Text: text, HasBody: hasBody, Error: err}
+			}
+		}()
+	}
+
+	for i := range docs {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,139 @@
+package extract
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// metaContent は、property 属性または name 属性が keys のいずれかに一致する
+// meta 要素の content を、keys の順に探索して返します。
+func metaContent(doc *goquery.Document, keys ...string) string {
+	for _, key := range keys {
+		var content string
+		doc.Find("meta[content]").EachWithBreak(func(i int, s *goquery.Selection) bool {
+			property := s.AttrOr("property", s.AttrOr("name", ""))
+			if !strings.EqualFold(strings.TrimSpace(property), key) {
+				return true
+			}
+			content = strings.TrimSpace(s.AttrOr("content", ""))
+			return content == ""
+		})
+		if content != "" {
+			return content
+		}
+	}
+	return ""
+}
+
+// metaContents は、property 属性または name 属性が key に一致するすべての meta 要素の content を
+// 出現順に返します。空の値と重複する値は除きます。
+func metaContents(doc *goquery.Document, key string) []string {
+	var contents []string
+	seen := make(map[string]bool)
+	doc.Find("meta[content]").Each(func(i int, s *goquery.Selection) {
+		property := s.AttrOr("property", s.AttrOr("name", ""))
+		if !strings.EqualFold(strings.TrimSpace(property), key) {
+			return
+		}
+		content := strings.TrimSpace(s.AttrOr("content", ""))
+		if content != "" && !seen[content] {
+			seen[content] = true
+			contents = append(contents, content)
+		}
+	})
+	return contents
+}
+
+// findDescription はページの概要文を og:description、meta description の順に取得します。
+func findDescription(doc *goquery.Document) string {
+	return metaContent(doc, "og:description", "description")
+}
+
+// SocialMetadata は、SNS のプレビュー表示に使われる Open Graph / Twitter Card のメタデータです。
+// 両方に宣言がある項目は Open Graph を優先し、Twitter Card はその補完として扱います。
+type SocialMetadata struct {
+	Title       string // og:title または twitter:title
+	Description string // og:description または twitter:description
+	Image       string // og:image または twitter:image の絶対URL
+	Card        string // twitter:card (例: "summary_large_image")
+}
+
+// findSocialMetadata は Open Graph と Twitter Card のメタデータを読み取ります。
+func findSocialMetadata(doc *goquery.Document, pageURL string) SocialMetadata {
+	return SocialMetadata{
+		Title:       metaContent(doc, "og:title", "twitter:title"),
+		Description: metaContent(doc, "og:description", "twitter:description"),
+		Image:       resolveURL(documentBaseURL(doc, pageURL), metaContent(doc, "og:image", "twitter:image", "twitter:image:src")),
+		Card:        metaContent(doc, "twitter:card"),
+	}
+}
+
+// faviconRels はファビコンとして扱う link 要素の rel 値です。
+var faviconRels = map[string]bool{
+	"icon":                         true,
+	"apple-touch-icon":             true,
+	"apple-touch-icon-precomposed": true,
+}
+
+// findFavicon は link 要素からファビコンの絶対URLを取得します。
+// 複数宣言されている場合は sizes 属性が最も大きいものを優先し、
+// 宣言が無い場合はホスト直下の /favicon.ico を返します。
+func findFavicon(doc *goquery.Document, pageURL string) string {
+	base := documentBaseURL(doc, pageURL)
+
+	var best string
+	bestSize := -1
+	doc.Find("link[rel][href]").Each(func(i int, s *goquery.Selection) {
+		if !isFaviconLink(s.AttrOr("rel", "")) {
+			return
+		}
+		href := resolveURL(base, s.AttrOr("href", ""))
+		if href == "" {
+			return
+		}
+		if size := iconSize(s.AttrOr("sizes", "")); size > bestSize {
+			best, bestSize = href, size
+		}
+	})
+	if best != "" {
+		return best
+	}
+
+	// /favicon.ico は base 要素ではなくページのホストから取得します
+	page := parseBaseURL(pageURL)
+	if page == nil {
+		return ""
+	}
+	return page.Scheme + "://" + page.Host + "/favicon.ico"
+}
+
+// isFaviconLink は rel 属性にファビコンを示す値が含まれるかを判定します。
+func isFaviconLink(rel string) bool {
+	for _, token := range strings.Fields(strings.ToLower(rel)) {
+		if faviconRels[token] {
+			return true
+		}
+	}
+	return false
+}
+
+// iconSize は sizes 属性 (例: "16x16 32x32") から最大の一辺の長さを返します。
+// "any" はベクター画像を示すため最大として扱い、宣言が無い場合は 0 を返します。
+func iconSize(sizes string) int {
+	largest := 0
+	for _, size := range strings.Fields(strings.ToLower(sizes)) {
+		if size == "any" {
+			return int(^uint(0) >> 1)
+		}
+		width, _, ok := strings.Cut(size, "x")
+		if !ok {
+			continue
+		}
+		if n, err := strconv.Atoi(width); err == nil && n > largest {
+			largest = n
+		}
+	}
+	return largest
+}
@@ -0,0 +1,365 @@
+package extract
+
+import "strings"
+
+// Option は Extractor の設定を行うための関数型です。
+type Option func(*Extractor)
+
+// WithExtractInlineJSON は、本文を抽出できなかった場合に、
+// インラインスクリプトに埋め込まれた初期データ (JSON) を抽出結果に含めるかを設定します。
+// SPA など、本文がJavaScriptで描画されるページからデータを取得する際に使用します。
+func WithExtractInlineJSON(enabled bool) Option {
+	return func(e *Extractor) {
+		e.extractInlineJSON = enabled
+	}
+}
+
+// WithDescriptionFallback は、本文を抽出できずタイトルのみとなった場合に、
+// og:description または meta description の内容を結果に追記するかを設定します。
+// 追記した場合でも hasBodyFound は false のまま返されます。
+func WithDescriptionFallback(enabled bool) Option {
+	return func(e *Extractor) {
+		e.descriptionFallback = enabled
+	}
+}
+
+// WithAllowTitleOnly は、本文を抽出できずタイトルのみとなった結果を成功として扱うかを設定します。
+// 有効な場合、タイトルを抽出できていればテキスト抽出系のメソッドは hasBodyFound に true を返すため、
+// scraper.Concurrent は !hasBodyFound の分岐で本文未検出のエラー (ports.ErrNoContent) を設定しません。
+// タイトルと本文のいずれも無いページは引き続き ErrNoContent を返します。
+// ExtractionResult.HasBody は本文の有無をそのまま表し、この設定の影響を受けません。
+// デフォルトは false (タイトルのみの結果は hasBodyFound が false) です。
+func WithAllowTitleOnly(enabled bool) Option {
+	return func(e *Extractor) {
+		e.allowTitleOnly = enabled
+	}
+}
+
+// WithHeadingMinLengths は、見出しレベル (1〜6) ごとに本文として採用する最小文字数を設定します。
+// 指定の無いレベルには MinHeadingLength が適用されます。
+func WithHeadingMinLengths(minLengths map[int]int) Option {
+	return func(e *Extractor) {
+		e.headingMinLengths = make(map[int]int, len(minLengths))
+		for level, n := range minLengths {
+			e.headingMinLengths[level] = n
+		}
+	}
+}
+
+// WithOutputFormat は抽出テキストの出力形式を設定します。デフォルトは FormatPlainText です。
+// FormatMarkdown では見出しレベルとリストの記号 (番号付きリストの番号を含む)、リンクと強調を保持し、
+// WithTableStyle の指定が無い限り表を GFM 形式で出力します。
+func WithOutputFormat(format OutputFormat) Option {
+	return func(e *Extractor) {
+		e.outputFormat = format
+	}
+}
+
+// WithFrontmatter は、Markdown 形式の出力の先頭に YAML frontmatter
+// (title, url, author, published, language) を付与するかを設定します。
+// 値が空のフィールドは省略されます。FormatMarkdown 以外では無視されます。
+func WithFrontmatter(enabled bool) Option {
+	return func(e *Extractor) {
+		e.frontmatter = enabled
+	}
+}
+
+// WithForcedCharset は、HTMLの文字コードを強制的に指定します (例: "shift_jis")。
+// Content-Type ヘッダーの charset よりも優先されるため、サーバーが誤った宣言をする場合に使用します。
+func WithForcedCharset(label string) Option {
+	return func(e *Extractor) {
+		e.forcedCharset = strings.TrimSpace(label)
+	}
+}
+
+// WithFollowIframes は、本文を抽出できなかった場合に、同一オリジンの iframe の src を取得して
+// そこから本文を抽出するかを設定します。辿るのは1階層のみで、別オリジンの iframe は対象外です。
+func WithFollowIframes(enabled bool) Option {
+	return func(e *Extractor) {
+		e.followIframes = enabled
+	}
+}
+
+// WithKeepShortLines は、短い段落が連続して並ぶ場合 (チャットログや書き起こしなど) に、
+// MinParagraphLength 未満の段落も本文として保持するかを設定します。
+// 単独の短い段落は従来どおり除外されます。
+func WithKeepShortLines(enabled bool) Option {
+	return func(e *Extractor) {
+		e.keepShortLines = enabled
+	}
+}
+
+// WithNormalizeUnicode は、テキスト正規化の際に Unicode 正規化 (NFKC) を適用するかを設定します。
+// 全角英数字 (例: "１２３") は半角 ("123") に変換され、MinParagraphLength などの長さ判定にも
+// 変換後の文字列が使われます。デフォルトは無効で、元の文字をそのまま出力します。
+func WithNormalizeUnicode(enabled bool) Option {
+	return func(e *Extractor) {
+		e.normalizeUnicode = enabled
+	}
+}
+
+// WithExtractTimes は、time 要素の datetime 属性を解析し、
+// ExtractionResult.Times に収集するかを設定します。イベントや予定表ページの解析に使用します。
+func WithExtractTimes(enabled bool) Option {
+	return func(e *Extractor) {
+		e.extractTimes = enabled
+	}
+}
+
+// WithTableStyle はテーブルの出力形式を設定します。
+// デフォルトは TableStylePlain で、FormatMarkdown の出力では TableStyleMarkdown です。
+func WithTableStyle(style TableStyle) Option {
+	return func(e *Extractor) {
+		e.tableStyle = style
+		e.tableStyleSet = true
+	}
+}
+
+// WithFallbackRelaxed は、通常の閾値で本文を抽出できなかった場合に、
+// 段落・見出しの最小文字数を緩和した2回目の抽出を行うかを設定します。
+// 2回目の抽出で本文が得られたかは ExtractionResult.Relaxed で確認できます。
+func WithFallbackRelaxed(enabled bool) Option {
+	return func(e *Extractor) {
+		e.fallbackRelaxed = enabled
+	}
+}
+
+// WithMaxHTMLBytes は、解析するHTMLの最大バイト数を設定します。
+// 超過分はタグやマルチバイト文字の途中を避けて切り捨てられ、巨大なページの解析コストを抑えます。
+// 本文がページ先頭付近にあると分かっている場合に使用してください。0 以下の場合は無制限です。
+func WithMaxHTMLBytes(n int) Option {
+	return func(e *Extractor) {
+		if n > 0 {
+			e.maxHTMLBytes = n
+		}
+	}
+}
+
+// WithTitleSource は、ページタイトルの取得元の優先順位を設定します。
+// TitleSourceOGTitle、TitleSourceTitle、TitleSourceH1、TitleSourceTwitterTitle、TitleSourceJSONLD を指定でき、
+// 先頭から順に空でない値が得られた取得元を採用します。デフォルトは title 要素のみです。
+func WithTitleSource(order []string) Option {
+	return func(e *Extractor) {
+		e.titleSources = append([]string(nil), order...)
+	}
+}
+
+// WithStripSiteSuffix は、タイトル末尾の " - サイト名" や " | サイト名" を取り除くかを設定します。
+// サイト名は og:site_name から取得し、宣言が無いページでは何もしません。
+func WithStripSiteSuffix(enabled bool) Option {
+	return func(e *Extractor) {
+		e.stripSiteSuffix = enabled
+	}
+}
+
+// WithExtractBreadcrumbs は、パンくずリストを抽出して ExtractionResult.Breadcrumbs に設定するかを設定します。
+// ページのカテゴリ階層を分類に利用する用途を想定しています。
+func WithExtractBreadcrumbs(enabled bool) Option {
+	return func(e *Extractor) {
+		e.extractBreadcrumbs = enabled
+	}
+}
+
+// WithDropPhrases は、抽出後に除去する定型文を設定します。
+// 段落・見出し・リスト項目のうち、いずれかの定型文と一致する、または含むもの (部分一致) を本文から除去します。
+// 比較は本文と同じ正規化を適用した上で行います。
+func WithDropPhrases(phrases []string) Option {
+	return func(e *Extractor) {
+		e.customDropPhrases = append([]string(nil), phrases...)
+	}
+}
+
+// WithDefaultDropPhrases は、DefaultDropPhrases を除去対象に加えるかを設定します。
+// WithDropPhrases で指定した定型文と併用できます。既定の定型文は部分一致ではなく、
+// 要素のテキストが定型文とほぼ同じ長さの場合にのみ除去します。
+func WithDefaultDropPhrases(enabled bool) Option {
+	return func(e *Extractor) {
+		e.defaultDropPhrases = enabled
+	}
+}
+
+// WithDropPhrasesIgnoreCase は、定型文の比較で大文字と小文字を区別しないかを設定します。
+func WithDropPhrasesIgnoreCase(enabled bool) Option {
+	return func(e *Extractor) {
+		e.dropPhrasesIgnoreCase = enabled
+	}
+}
+
+// WithMinTableRows は、本文として出力するテーブルの最小行数を設定します。
+// 行数がこれに満たないテーブルはレイアウト目的とみなして出力しません。
+// デフォルトでは行数による除外を行いません。
+func WithMinTableRows(n int) Option {
+	return func(e *Extractor) {
+		if n > 0 {
+			e.minTableRows = n
+		}
+	}
+}
+
+// WithMinTableColumns は、本文として出力するテーブルの最小列数を設定します。
+// 列数がこれに満たないテーブルはレイアウト目的とみなして出力しません。
+// デフォルトでは列数による除外を行いません。
+func WithMinTableColumns(n int) Option {
+	return func(e *Extractor) {
+		if n > 0 {
+			e.minTableColumns = n
+		}
+	}
+}
+
+// WithConcurrentExtraction は、ExtractMany で同時に解析するドキュメント数の上限を設定します。
+// 0 以下の場合は GOMAXPROCS の値を使用します。
+func WithConcurrentExtraction(workers int) Option {
+	return func(e *Extractor) {
+		if workers > 0 {
+			e.extractWorkers = workers
+		}
+	}
+}
+
+// WithAllowedContentTypes は、ExtractTextWithContentType で処理する Content-Type を限定します。
+// 例: WithAllowedContentTypes("text/html", "application/xhtml+xml", "text/plain")
+// 許可されていない Content-Type には ErrUnsupportedContentType を返します。
+// text/plain を許可した場合、その本文はHTMLとして解析せず整形済みテキストとして扱います。
+// 未設定の場合は、Content-Type に関わらずHTMLとして解析します。
+func WithAllowedContentTypes(types ...string) Option {
+	return func(e *Extractor) {
+		e.allowedContentTypes = make([]string, 0, len(types))
+		for _, t := range types {
+			if t = mediaTypeOf(t); t != "" {
+				e.allowedContentTypes = append(e.allowedContentTypes, t)
+			}
+		}
+	}
+}
+
+// WithDetectSoft404 は、ステータスコード 200 で返される「ページが見つかりません」ページ (soft 404) を検出するかを設定します。
+// 本文が短く (500文字以下)、タイトルまたは本文が soft 404 のキーワードを含む場合に該当すると判定し、
+// テキスト抽出系のメソッドは ErrSoftNotFound を返し、FetchAndExtract は ExtractionResult.SoftNotFound を true にします。
+func WithDetectSoft404(enabled bool) Option {
+	return func(e *Extractor) {
+		e.detectSoft404 = enabled
+	}
+}
+
+// WithSoft404Keywords は、soft 404 の判定に用いるキーワードを設定します。
+// 指定したキーワードは DefaultSoft404Keywords を置き換えます。大文字と小文字は区別しません。
+func WithSoft404Keywords(keywords []string) Option {
+	return func(e *Extractor) {
+		e.soft404Keywords = append([]string{}, keywords...)
+	}
+}
+
+// WithPreserveCodeIndentation は、pre 要素 (コードブロック) の前後の空行のみを取り除き、
+// 先頭行を含む各行のインデントを保持するかを設定します。
+// Python や YAML のようにインデントが意味を持つコードを抽出する場合に使用します。
+// デフォルトではコードブロック全体の前後の空白を取り除きます。
+func WithPreserveCodeIndentation(enabled bool) Option {
+	return func(e *Extractor) {
+		e.preserveCodeIndent = enabled
+	}
+}
+
+// WithEmojiHandling は、抽出テキスト中の絵文字の扱いを設定します。デフォルトは EmojiKeep です。
+// 絵文字がトークン化の妨げになる自然言語処理モデルへ入力する場合などに、EmojiStrip または EmojiDescribe を指定します。
+func WithEmojiHandling(mode EmojiHandling) Option {
+	return func(e *Extractor) {
+		e.emojiHandling = mode
+	}
+}
+
+// WithMaxParagraphs は、本文に含める段落 (p, blockquote) の最大数を設定します。
+// 上限に達した後の段落は読み飛ばしますが、見出しや表、コードブロックは引き続き収集します。
+// 抽出結果から直接プレビュー用の抜粋を作る用途を想定しています。0 以下の場合は無制限です。
+func WithMaxParagraphs(n int) Option {
+	return func(e *Extractor) {
+		if n > 0 {
+			e.maxParagraphs = n
+		}
+	}
+}
+
+// WithStopAtMaxParagraphs は、WithMaxParagraphs の上限に達した時点で、
+// 見出しや表などを含むすべてのパーツの収集を終了するかを設定します。
+func WithStopAtMaxParagraphs(enabled bool) Option {
+	return func(e *Extractor) {
+		e.stopAtMaxParagraphs = enabled
+	}
+}
+
+// WithNoiseSelectors は、メインコンテンツから除去するノイズ要素のセレクターを設定します。
+// sel は goquery.Find に渡す CSS セレクターで、既定のセレクター
+// (".related-posts, .social-share, .comments, .ad-banner, .advertisement") を置き換えます。
+// Cookie バナーやニュースレターの登録欄など、サイト固有の要素の除去に使用します。
+// 空文字列の場合は既定のセレクターを使用します。
+func WithNoiseSelectors(sel string) Option {
+	return func(e *Extractor) {
+		e.noiseSelectors = strings.TrimSpace(sel)
+	}
+}
+
+// WithMainContentSelectors は、メインコンテンツを特定するセレクターを設定します。
+// sel は goquery.Find に渡す CSS セレクターで、既定のセレクター ("article, main, #content" など) を置き換えます。
+// 最初に一致した要素をメインコンテンツとし、一致しない場合はページ全体から本文を抽出します。
+// 空文字列の場合は既定のセレクターを使用します。
+func WithMainContentSelectors(sel string) Option {
+	return func(e *Extractor) {
+		e.mainContentSelectors = strings.TrimSpace(sel)
+	}
+}
+
+// WithContentDetection は、メインコンテンツの特定方法を設定します。デフォルトは ContentDetectionSelectors です。
+// 既知の構造を持たないブログなどで、コメント欄を含むページ全体が本文として抽出される場合に
+// ContentDetectionFallback または ContentDetectionScoring を指定します。
+func WithContentDetection(mode ContentDetection) Option {
+	return func(e *Extractor) {
+		e.contentDetection = mode
+	}
+}
+
+// WithMaxLinkDensity は、段落 (p) とリスト項目 (li) を本文として採用するリンク密度の上限を設定します。
+// リンク密度は要素の文字数に対する a 要素内の文字数の割合で、これを超える要素は
+// 関連記事の一覧などのナビゲーションとみなして除外します。見出しには適用しません。
+// デフォルトは DefaultMaxLinkDensity (0.5) です。1 を指定すると除外を行いません。0 以下の値は無視されます。
+func WithMaxLinkDensity(ratio float64) Option {
+	return func(e *Extractor) {
+		if ratio > 0 {
+			e.maxLinkDensity = ratio
+		}
+	}
+}
+
+// WithReadingSpeed は、ExtractionResult.ReadingTimeSeconds の推定に用いる読書速度 (1分あたりの語数) を設定します。
+// デフォルトは DefaultReadingSpeed です。0 以下の値は無視されます。
+func WithReadingSpeed(wpm int) Option {
+	return func(e *Extractor) {
+		if wpm > 0 {
+			e.readingSpeed = wpm
+		}
+	}
+}
+
+// WithExcludeCodeFromReadingStats は、ExtractionResult の語数と読了時間の推定から
+// コードブロックを除外するかを設定します。デフォルトではコードブロックも数えます。
+func WithExcludeCodeFromReadingStats(enabled bool) Option {
+	return func(e *Extractor) {
+		e.excludeCodeFromStats = enabled
+	}
+}
+
+// WithTitlePrefix は、テキスト抽出の結果でタイトルの行に付ける接頭辞を設定します。
+// デフォルトは DefaultTitlePrefix ("【記事タイトル】 ") で、空文字列を指定すると接頭辞を付けません。
+func WithTitlePrefix(prefix string) Option {
+	return func(e *Extractor) {
+		e.titlePrefix = prefix
+	}
+}
+
+// WithTableCaptionPrefix は、テーブルの表題 (caption) の行に付ける接頭辞を設定します。
+// デフォルトは DefaultTableCaptionPrefix ("【表題】 ") で、空文字列を指定すると接頭辞を付けません。
+// GFM 形式のテーブルでは表題を太字で出力するため、この設定は使用されません。
+func WithTableCaptionPrefix(prefix string) Option {
+	return func(e *Extractor) {
+		e.tableCaptionPrefix = prefix
+	}
+}
@@ -0,0 +1,75 @@
+package extract
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultDropPhrases は、WithDefaultDropPhrases(true) で除去対象となる定型文です。
+// 既知のノイズセレクターで囲まれていない共有ボタンや広告表記などを想定しています。
+// 「今年の広告市場は…」のような本文を除去しないよう、要素のテキストが定型文そのもの、
+// または定型文より defaultDropPhraseMaxExtraRunes 文字以内だけ長い場合にのみ一致とみなします。
+var DefaultDropPhrases = []string{
+	"Share this article",
+	"Advertisement",
+	"Read more",
+	"この記事をシェア",
+	"広告",
+	"続きを読む",
+}
+
+// defaultDropPhraseMaxExtraRunes は、DefaultDropPhrases の定型文を含む要素を除去する際に
+// 許容する、定型文以外の文字数 (rune 数) の上限です。
+const defaultDropPhraseMaxExtraRunes = 12
+
+// dropPhrase は、比較用に正規化済みの除去対象の定型文です。
+type dropPhrase struct {
+	text  string
+	runes int
+	// nearExact が true の場合は、要素のテキストが定型文とほぼ同じ長さの場合にのみ一致とみなします。
+	// false の場合 (WithDropPhrases で指定した定型文) は部分一致で判定します。
+	nearExact bool
+}
+
+// prepareDropPhrases は、オプションの適用後に除去対象の定型文を本文と同じ方法で正規化して保持します。
+// 正規化は Unicode 正規化や絵文字の設定に依存するため、すべてのオプションを適用した後に呼び出します。
+func (e *Extractor) prepareDropPhrases() {
+	e.dropPhrases = nil
+	add := func(phrases []string, nearExact bool) {
+		for _, phrase := range phrases {
+			phrase = e.normalizeText(phrase)
+			if e.dropPhrasesIgnoreCase {
+				phrase = strings.ToLower(phrase)
+			}
+			if phrase == "" {
+				continue
+			}
+			e.dropPhrases = append(e.dropPhrases, dropPhrase{text: phrase, runes: utf8.RuneCountInString(phrase), nearExact: nearExact})
+		}
+	}
+	if e.defaultDropPhrases {
+		add(DefaultDropPhrases, true)
+	}
+	add(e.customDropPhrases, false)
+}
+
+// containsDropPhrase は、正規化済みのテキストが除去対象の定型文に該当するかを判定します。
+// DefaultDropPhrases はほぼ完全な一致、WithDropPhrases の定型文は部分一致で判定します。
+func (e *Extractor) containsDropPhrase(content string) bool {
+	if len(e.dropPhrases) == 0 {
+		return false
+	}
+	if e.dropPhrasesIgnoreCase {
+		content = strings.ToLower(content)
+	}
+	contentRunes := utf8.RuneCountInString(content)
+	for _, phrase := range e.dropPhrases {
+		if phrase.nearExact && contentRunes > phrase.runes+defaultDropPhraseMaxExtraRunes {
+			continue
+		}
+		if strings.Contains(content, phrase.text) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package extract
+
+import (
+	"slices"
+	"unicode"
+)
+
+// DefaultReadingSpeed は、読了時間の推定に用いる既定の読書速度 (1分あたりの語数) です。
+// 日本語などの CJK 文字は1文字を1語として数えます。
+const DefaultReadingSpeed = 200
+
+// countWords は s の語数を数えます。
+// 漢字・ひらがな・カタカナ・ハングルは1文字を1語として数え、
+// それ以外は空白と CJK 文字で区切られた、文字または数字を含む並びを1語として数えます。
+func countWords(s string) int {
+	count := 0
+	inWord := false
+	for _, r := range s {
+		switch {
+		case isCJK(r):
+			count++
+			inWord = false
+		case unicode.IsSpace(r):
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if !inWord {
+				count++
+				inWord = true
+			}
+		}
+		// 記号は語の区切りにも語の一部にもしません ("don't" や "3.14" を1語として数えます)
+	}
+	return count
+}
+
+// isCJK は r が1文字を1語として数える CJK の文字であるかを判定します。
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+// readingStats は、タイトルと本文のパーツから語数と読了時間 (秒) を推定します。
+// WithExcludeCodeFromReadingStats が有効な場合は、収集時に pre 要素から得たパーツを数えません。
+func (e *Extractor) readingStats(collected collectedParts) (words, seconds int) {
+	words = countWords(collected.title)
+	for i, part := range collected.body {
+		if e.excludeCodeFromStats && slices.Contains(collected.codeParts, i) {
+			continue
+		}
+		words += countWords(part)
+	}
+
+	wpm := e.readingSpeed
+	if wpm <= 0 {
+		wpm = DefaultReadingSpeed
+	}
+	// 1語でもあれば最低1秒とし、端数は切り上げます
+	seconds = (words*60 + wpm - 1) / wpm
+	return words, seconds
+}
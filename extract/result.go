@@ -0,0 +1,177 @@
+package extract
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ExtractionResult は、1ページ分の抽出結果を構造化して保持します。
+type ExtractionResult struct {
+	Title   string // ページタイトル (WithTitleSource の指定が無い場合は og:title を優先)
+	Body    string // 整形済みの本文 (タイトルを含みません)
+	HasBody bool   // 本文が検出された場合は true
+	Favicon string // ファビコンの絶対URL
+	// Tables は、本文中の表を出現順に整形したものです。各表は Body にも含まれます。
+	Tables []string
+	// CodeBlocks は、本文中のコードブロック (pre 要素) の内容です。フェンス (```) を含みません。
+	// 各コードブロックはフェンス付きで Body にも含まれます。
+	CodeBlocks []string
+	// Paragraphs は、本文中の段落 (p, blockquote 要素) を出現順に整形したものです。
+	// 見出し、リスト項目、表、コードブロックを含みません。各段落は Body にも含まれます。
+	Paragraphs []string
+	// Description は、og:description または meta description の内容です。宣言が無い場合は空文字列です。
+	Description string
+	// OGImage は、og:image で宣言された画像の絶対URLです。宣言が無い場合は空文字列です。
+	// twitter:image で補完した値は Social.Image を参照してください。
+	OGImage string
+	// PublishedAt は、meta 要素、JSON-LD の datePublished、time 要素から解析した公開日時です。
+	// 解析できる値が無い場合は nil です。
+	PublishedAt *time.Time
+	// Author は、meta name="author"、rel="author" のリンク、JSON-LD の author から取得した著者名です。
+	// 複数の著者が宣言されている場合は ", " で連結します。宣言が無い場合は空文字列です。
+	Author string
+	// Article は、JSON-LD で宣言された記事 (Article、NewsArticle、BlogPosting) のメタデータです。
+	// 宣言が無い場合は nil です。解析できない JSON-LD は無視されます。
+	Article *JSONLDArticle
+	// WordCount は、タイトルと本文 (表を含む) の語数です。CJK の文字は1文字を1語として数えます。
+	// WithExcludeCodeFromReadingStats(true) の指定時はコードブロックを含みません。
+	WordCount int
+	// ReadingTimeSeconds は、WordCount と WithReadingSpeed の読書速度から推定した読了時間 (秒) です。
+	ReadingTimeSeconds int
+	// Social は、Open Graph と Twitter Card から読み取ったプレビュー用のメタデータです。
+	Social SocialMetadata
+	// Section は article:section で宣言された記事のセクション (カテゴリ) です。宣言が無い場合は空文字列です。
+	Section string
+	// Tags は article:tag で宣言された記事のタグを出現順に並べたものです。
+	Tags []string
+	// TitleCandidates は、タイトルの取得元ごとに得られた値と採用された候補です。
+	// WithTitleSource の優先順位を調整する際の診断に利用します。
+	TitleCandidates []TitleCandidate
+	// InlineJSON は、本文を抽出できなかった場合にインラインスクリプトから見つかったJSON文字列です。
+	// WithExtractInlineJSON(true) を指定した場合にのみ設定されます。
+	InlineJSON []string
+	// Times は、time 要素の datetime 属性を解析した日時です。
+	// WithExtractTimes(true) を指定した場合にのみ設定されます。
+	Times []time.Time
+	// Breadcrumbs は、パンくずリストの項目を上位の階層から順に並べたものです (例: ["Home", "Tech", "AI"])。
+	// WithExtractBreadcrumbs(true) を指定した場合にのみ設定されます。
+	Breadcrumbs []string
+	// Relaxed は、WithFallbackRelaxed による閾値を緩和した2回目の抽出で本文が得られた場合に true になります。
+	Relaxed bool
+	// SoftNotFound は、WithDetectSoft404(true) の指定時に、ページが「ページが見つかりません」を示す
+	// 内容 (soft 404) であると判定された場合に true になります。
+	SoftNotFound bool
+}
+
+// FetchAndExtract は指定されたURLからコンテンツを取得し、構造化された抽出結果を返します。
+func (e *Extractor) FetchAndExtract(ctx context.Context, url string) (*ExtractionResult, error) {
+	htmlBytes, err := e.fetcher.FetchBytes(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := e.parseDocument(ctx, bytes.NewReader(htmlBytes), "")
+	if err != nil {
+		return nil, err
+	}
+
+	var frameURL string
+	if e.followIframes {
+		frameURL = findSameOriginIframe(doc, url)
+	}
+
+	result, err := e.extractResult(ctx, doc, url)
+
+	// 本文が得られない場合は同一オリジンの iframe の本文で補完します
+	if frameURL != "" && (err != nil || !result.HasBody) && ctx.Err() == nil {
+		if frameDoc, ok := e.fetchIframeDocument(ctx, frameURL); ok {
+			if frameResult, frameErr := e.extractResult(ctx, frameDoc, frameURL); frameErr == nil && frameResult.HasBody {
+				if result == nil {
+					return frameResult, nil
+				}
+				result.copyBodyFrom(frameResult)
+				return result, nil
+			}
+		}
+	}
+	return result, err
+}
+
+// copyBodyFrom は、本文から導出されるフィールドを src の値で置き換えます。
+// タイトルやメタデータは親ページの値を保持します。
+func (r *ExtractionResult) copyBodyFrom(src *ExtractionResult) {
+	r.Body = src.Body
+	r.HasBody = src.HasBody
+	r.Tables = src.Tables
+	r.CodeBlocks = src.CodeBlocks
+	r.Paragraphs = src.Paragraphs
+	r.InlineJSON = src.InlineJSON
+	r.WordCount, r.ReadingTimeSeconds = src.WordCount, src.ReadingTimeSeconds
+	r.Relaxed = src.Relaxed
+	r.SoftNotFound = src.SoftNotFound
+}
+
+// extractResult はgoquery.Documentから構造化された抽出結果を組み立てます。
+// WithTitleSource の指定が無い場合、Title は og:title を title 要素より優先します。
+// 両者が異なる場合でも、title 要素の値は TitleCandidates から参照できます。
+func (e *Extractor) extractResult(ctx context.Context, doc *goquery.Document, pageURL string) (*ExtractionResult, error) {
+	if len(e.titleSources) == 0 {
+		e = e.withOptions([]Option{WithTitleSource(resultTitleSources)})
+	}
+
+	// メタデータはノイズ除去でDOMが変更される前に読み取ります
+	result := &ExtractionResult{
+		Favicon: findFavicon(doc, pageURL),
+		Social:  findSocialMetadata(doc, pageURL),
+
+		Description: findDescription(doc),
+		OGImage:     resolveURL(documentBaseURL(doc, pageURL), metaContent(doc, "og:image")),
+	}
+	result.Section = metaContent(doc, "article:section")
+	result.Tags = metaContents(doc, "article:tag")
+	// JSON-LD は公開日時、著者、記事、パンくずリストで共有するため一度だけ解析します
+	jsonLD := jsonLDObjects(doc)
+	result.PublishedAt = findPublishedAt(doc, jsonLD)
+	result.Author = findAuthor(doc, jsonLD)
+	result.Article = findJSONLDArticle(jsonLD)
+	result.TitleCandidates = e.titleCandidates(doc, result.Article)
+
+	if e.extractTimes {
+		result.Times = findTimes(doc)
+	}
+
+	if e.extractBreadcrumbs {
+		result.Breadcrumbs = findBreadcrumbs(doc, jsonLD)
+	}
+
+	var inlineJSON []string
+	if e.extractInlineJSON {
+		inlineJSON = findInlineJSON(doc)
+	}
+
+	collected, relaxed, err := e.collectPartsWithFallback(ctx, doc, pageURL)
+	if err != nil {
+		return nil, err
+	}
+	title, bodyParts := collected.title, collected.body
+	if len(bodyParts) == 0 {
+		result.InlineJSON = inlineJSON
+	}
+	if title == "" && len(bodyParts) == 0 && len(result.InlineJSON) == 0 {
+		return nil, ErrNoContent
+	}
+
+	result.Title = title
+	result.Body = joinParts(bodyParts)
+	result.HasBody = len(bodyParts) > 0
+	result.Tables = collected.tables
+	result.CodeBlocks = collected.codeBlocks
+	result.Paragraphs = collected.paragraphs
+	result.WordCount, result.ReadingTimeSeconds = e.readingStats(collected)
+	result.Relaxed = relaxed
+	result.SoftNotFound = e.detectSoft404 && e.isSoft404(title, bodyParts)
+	return result, nil
+}
@@ -0,0 +1,72 @@
+package extract
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxSlugLength は Slugify が生成するスラッグの最大文字数 (rune 数) です。
+const MaxSlugLength = 80
+
+// Slugify は、タイトルからURLやファイル名として安全に使えるスラッグを生成します。
+// 英字は小文字化してアクセント記号を除去し、漢字・かな・数字はそのまま保持します。
+// 空白や区切り記号はハイフンに置換し、それ以外の記号は除去したうえで
+// MaxSlugLength 文字に切り詰めます。
+func Slugify(title string) string {
+	// 全角英数字などを半角に揃えてから処理します
+	title = strings.ToLower(norm.NFKC.String(title))
+
+	var b strings.Builder
+	pendingHyphen := false
+	length := 0
+	for _, r := range title {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsNumber(r):
+			for _, c := range transliterate(r) {
+				if pendingHyphen && length > 0 {
+					if length+1 >= MaxSlugLength {
+						return b.String()
+					}
+					b.WriteByte('-')
+					length++
+				}
+				pendingHyphen = false
+				if length >= MaxSlugLength {
+					return b.String()
+				}
+				b.WriteRune(c)
+				length++
+			}
+		case unicode.IsSpace(r) || isSlugSeparator(r):
+			pendingHyphen = true
+		}
+		// その他の記号 (句読点・括弧など) は除去します
+	}
+	return b.String()
+}
+
+// transliterate はラテン文字からアクセント記号などの結合文字を取り除きます (例: é → e)。
+// かなの濁点のような他の文字体系の結合文字は保持します。
+func transliterate(r rune) []rune {
+	if !unicode.Is(unicode.Latin, r) {
+		return []rune{r}
+	}
+	var out []rune
+	for _, c := range norm.NFD.String(string(r)) {
+		if !unicode.Is(unicode.Mn, c) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// isSlugSeparator は単語の区切りとしてハイフンに置換する記号かを判定します。
+func isSlugSeparator(r rune) bool {
+	switch r {
+	case '-', '_', '/', '.', '・', '|', ':', '～', '〜':
+		return true
+	}
+	return unicode.Is(unicode.Pd, r)
+}
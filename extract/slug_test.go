@@ -0,0 +1,38 @@
+package extract_test
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/shouni/go-web-exact/v2/extract"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlugify(t *testing.T) {
+	testCases := []struct {
+		name     string
+		title    string
+		expected string
+	}{
+		{name: "ascii_title", title: "Hello, World! Go 1.26 Released", expected: "hello-world-go-1-26-released"},
+		{name: "accented_latin", title: "Café Crème", expected: "cafe-creme"},
+		{name: "japanese_title", title: "【速報】Go言語の新機能、ついに登場！", expected: "速報go言語の新機能ついに登場"},
+		{name: "mixed_script_with_separators", title: "ＧＯ入門 ― 第２章・ゴルーチン", expected: "go入門-第2章-ゴルーチン"},
+		{name: "dakuten_is_kept", title: "データベース ガイド", expected: "データベース-ガイド"},
+		{name: "symbols_only", title: "!!! ??? 。。。", expected: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, extract.Slugify(tc.title))
+		})
+	}
+
+	t.Run("truncated_to_max_length", func(t *testing.T) {
+		slug := extract.Slugify(strings.Repeat("長い タイトル ", 30))
+
+		assert.LessOrEqual(t, utf8.RuneCountInString(slug), extract.MaxSlugLength)
+		assert.False(t, strings.HasSuffix(slug, "-"))
+	})
+}
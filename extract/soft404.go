@@ -0,0 +1,56 @@
+package extract
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrSoftNotFound は、WithDetectSoft404 が有効な場合に、ステータスコード 200 で返された
+// 「ページが見つかりません」ページを検出したときにテキスト抽出系のメソッドが返すエラーです。
+var ErrSoftNotFound = errors.New("ページが存在しない (soft 404) と判定されました")
+
+// DefaultSoft404Keywords は、soft 404 ページの判定に用いる既定のキーワードです。
+// 大文字と小文字は区別しません。
+var DefaultSoft404Keywords = []string{
+	"page not found",
+	"404 not found",
+	"404 error",
+	"ページが見つかりません",
+	"お探しのページは見つかりませんでした",
+	"ページは存在しません",
+	"página no encontrada",
+	"seite nicht gefunden",
+	"page introuvable",
+}
+
+// soft404MaxBodyLength は、soft 404 と判定する本文の最大文字数 (rune 数) です。
+// 長い記事がタイトルや本文でキーワードに言及しているだけの場合 (「Nginx の 404 error を直す方法」など) に
+// 誤検出しないよう、タイトルと本文のどちらの一致にも適用します。
+const soft404MaxBodyLength = 500
+
+// isSoft404 は、本文が短く、タイトルまたは本文が「ページが見つかりません」を示すキーワードを含むかを判定します。
+func (e *Extractor) isSoft404(title string, bodyParts []string) bool {
+	body := strings.ToLower(joinParts(bodyParts))
+	if utf8.RuneCountInString(body) > soft404MaxBodyLength {
+		return false
+	}
+
+	keywords := e.soft404Keywords
+	if keywords == nil {
+		keywords = DefaultSoft404Keywords
+	}
+
+	title = strings.ToLower(title)
+
+	for _, keyword := range keywords {
+		keyword = strings.ToLower(strings.TrimSpace(keyword))
+		if keyword == "" {
+			continue
+		}
+		if strings.Contains(title, keyword) || strings.Contains(body, keyword) {
+			return true
+		}
+	}
+	return false
+}
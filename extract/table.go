@@ -0,0 +1,203 @@
+package extract
+
+import (
+	"encoding/csv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/rivo/uniseg"
+)
+
+// TableStyle はテーブルの出力形式を表します。
+type TableStyle int
+
+const (
+	// TableStylePlain は各行のセルを " | " で連結する従来の形式です (デフォルト)。
+	TableStylePlain TableStyle = iota
+	// TableStyleMarkdown は GFM 形式のパイプテーブルです。th を含む最初の行
+	// (th が無い場合は先頭行) をヘッダーとし、表題は太字の行として出力します。
+	TableStyleMarkdown
+	// TableStyleCSV は RFC 4180 に従ってクォートされた CSV 形式です。
+	TableStyleCSV
+	// TableStyleAligned は端末表示向けに列幅を揃えた固定幅形式です。
+	TableStyleAligned
+)
+
+// processTable は goquery.Selection からテーブルの内容を抽出し、整形します。
+func (e *Extractor) processTable(s *goquery.Selection) string {
+	captionText := strings.TrimSpace(s.Find("caption").First().Text())
+
+	var rows [][]string
+	headerRow := -1
+	s.Find("tr").Each(func(rowIndex int, row *goquery.Selection) {
+		var rowTexts []string
+		row.Find("th, td").Each(func(cellIndex int, cell *goquery.Selection) {
+			rowTexts = append(rowTexts, e.normalizeText(cell.Text()))
+		})
+		if headerRow < 0 && row.Find("th").Length() > 0 {
+			headerRow = rowIndex
+		}
+		rows = append(rows, rowTexts)
+	})
+
+	// 1セルのみのテーブルなど、レイアウト目的のテーブルは本文として扱いません
+	if e.isLayoutTable(rows) {
+		return ""
+	}
+
+	style := e.effectiveTableStyle()
+	if style == TableStyleMarkdown && headerRow > 0 {
+		// th を含む最初の行をヘッダーとして先頭に移動します
+		rows = append([][]string{rows[headerRow]}, append(rows[:headerRow:headerRow], rows[headerRow+1:]...)...)
+	}
+
+	var tableContent []string
+	if captionText != "" {
+		tableContent = append(tableContent, e.tableCaption(captionText, style))
+	}
+	tableContent = append(tableContent, e.renderTableRows(rows)...)
+	if len(tableContent) > 0 {
+		return strings.Join(tableContent, "\n")
+	}
+	return ""
+}
+
+// tableCaption は表題の行を返します。GFM テーブルでは太字の行とし、
+// 段落の続きと解釈されないよう表との間に空行を入れます。それ以外では WithTableCaptionPrefix の接頭辞を前置します。
+func (e *Extractor) tableCaption(caption string, style TableStyle) string {
+	if style == TableStyleMarkdown {
+		return "**" + caption + "**\n"
+	}
+	return e.tableCaptionPrefix + caption
+}
+
+// isLayoutTable は、テーブルの行数または列数が設定された最小値に満たないかを判定します。
+func (e *Extractor) isLayoutTable(rows [][]string) bool {
+	rows = nonEmptyRows(rows)
+	if e.minTableRows > 0 && len(rows) < e.minTableRows {
+		return true
+	}
+	return e.minTableColumns > 0 && columnCount(rows) < e.minTableColumns
+}
+
+// effectiveTableStyle は、出力形式を考慮した実際のテーブル形式を返します。
+// Markdown 出力で形式が明示されていない場合は GFM テーブルを使用します。
+func (e *Extractor) effectiveTableStyle() TableStyle {
+	if !e.tableStyleSet && e.outputFormat == FormatMarkdown {
+		return TableStyleMarkdown
+	}
+	return e.tableStyle
+}
+
+// renderTableRows は設定されたテーブル形式に従って各行を文字列に変換します。
+func (e *Extractor) renderTableRows(rows [][]string) []string {
+	switch e.effectiveTableStyle() {
+	case TableStyleMarkdown:
+		return renderMarkdownRows(rows)
+	case TableStyleCSV:
+		return renderCSVRows(rows)
+	case TableStyleAligned:
+		return renderAlignedRows(rows)
+	default:
+		lines := make([]string, 0, len(rows))
+		for _, row := range rows {
+			lines = append(lines, strings.Join(row, " | "))
+		}
+		return lines
+	}
+}
+
+// renderMarkdownRows は先頭行をヘッダーとして区切り行を挿入した GFM テーブルを生成します。
+// セル内のパイプ文字はエスケープし、列数が不足する行は空セルで補います。
+func renderMarkdownRows(rows [][]string) []string {
+	rows = nonEmptyRows(rows)
+	if len(rows) == 0 {
+		return nil
+	}
+
+	columns := columnCount(rows)
+	formatRow := func(row []string) string {
+		cells := make([]string, columns)
+		for i := range cells {
+			if i < len(row) {
+				cells[i] = strings.ReplaceAll(row[i], "|", `\|`)
+			}
+		}
+		return "| " + strings.Join(cells, " | ") + " |"
+	}
+
+	separator := make([]string, columns)
+	for i := range separator {
+		separator[i] = "---"
+	}
+
+	lines := []string{formatRow(rows[0]), "| " + strings.Join(separator, " | ") + " |"}
+	for _, row := range rows[1:] {
+		lines = append(lines, formatRow(row))
+	}
+	return lines
+}
+
+// renderCSVRows は各行を RFC 4180 に従ってクォートした CSV 行に変換します。
+func renderCSVRows(rows [][]string) []string {
+	rows = nonEmptyRows(rows)
+	if len(rows) == 0 {
+		return nil
+	}
+
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	// strings.Builder への書き込みは失敗しないため、エラーは無視します
+	_ = w.WriteAll(rows)
+	return []string{strings.TrimSuffix(b.String(), "\n")}
+}
+
+// renderAlignedRows は各列の表示幅を揃えた固定幅の行を生成します。
+// 全角文字は2桁として数えます。
+func renderAlignedRows(rows [][]string) []string {
+	rows = nonEmptyRows(rows)
+	if len(rows) == 0 {
+		return nil
+	}
+
+	widths := make([]int, columnCount(rows))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], uniseg.StringWidth(cell))
+		}
+	}
+
+	lines := make([]string, 0, len(rows))
+	for _, row := range rows {
+		cells := make([]string, len(widths))
+		for i := range widths {
+			var cell string
+			if i < len(row) {
+				cell = row[i]
+			}
+			cells[i] = cell + strings.Repeat(" ", widths[i]-uniseg.StringWidth(cell))
+		}
+		lines = append(lines, strings.TrimRight(strings.Join(cells, " | "), " "))
+	}
+	return lines
+}
+
+// nonEmptyRows はセルを持たない行を除外します。
+func nonEmptyRows(rows [][]string) [][]string {
+	var filtered [][]string
+	for _, row := range rows {
+		if len(row) > 0 {
+			filtered = append(filtered, row)
+		}
+	}
+	return filtered
+}
+
+// columnCount は最もセル数の多い行の列数を返します。
+func columnCount(rows [][]string) int {
+	columns := 0
+	for _, row := range rows {
+		columns = max(columns, len(row))
+	}
+	return columns
+}
@@ -0,0 +1,56 @@
+package extract
+
+import (
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// dateTimeLayouts は datetime 属性などの日時文字列を解析する際に試行するレイアウトです。
+// タイムゾーンを持たない形式は UTC として解釈されます。
+var dateTimeLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02",
+	"2006-01",
+	"2006",
+}
+
+// parseDateTime は日時文字列を dateTimeLayouts の順に解析します。
+func parseDateTime(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range dateTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// findTimes は time 要素の datetime 属性 (無い場合は表示テキスト) を解析して、
+// 文書内の出現順に返します。解析できない要素は無視します。
+func findTimes(doc *goquery.Document) []time.Time {
+	var times []time.Time
+	doc.Find("time").Each(func(i int, s *goquery.Selection) {
+		value, ok := s.Attr("datetime")
+		if !ok {
+			value = s.Text()
+		}
+		if t, ok := parseDateTime(value); ok {
+			times = append(times, t)
+		}
+	})
+	return times
+}
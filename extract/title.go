@@ -0,0 +1,137 @@
+package extract
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// WithTitleSource で指定できるタイトルの取得元です。
+const (
+	TitleSourceOGTitle      = "og:title"      // meta property="og:title"
+	TitleSourceTitle        = "title"         // title 要素
+	TitleSourceH1           = "h1"            // 最初の h1 要素
+	TitleSourceTwitterTitle = "twitter:title" // meta name="twitter:title"
+	TitleSourceJSONLD       = "jsonld"        // JSON-LD の記事 (Article など) の headline
+)
+
+// defaultTitleSources は従来どおり title 要素のみを参照する既定の優先順位です。
+var defaultTitleSources = []string{TitleSourceTitle}
+
+// resultTitleSources は FetchAndExtract の既定の優先順位です。
+// リンクプレビューでの利用を想定し、og:title を title 要素より優先します。
+var resultTitleSources = []string{TitleSourceOGTitle, TitleSourceTitle}
+
+// siteSuffixSeparators はタイトル末尾のサイト名の前に置かれる区切り文字です。
+var siteSuffixSeparators = []string{" - ", " | ", " — ", " – "}
+
+// allTitleSources は TitleCandidates に列挙する取得元です。
+var allTitleSources = []string{TitleSourceTitle, TitleSourceOGTitle, TitleSourceTwitterTitle, TitleSourceH1, TitleSourceJSONLD}
+
+// TitleCandidate は、タイトルの取得元ごとに得られた値です。WithTitleSource の順序を調整する際の診断に利用します。
+type TitleCandidate struct {
+	Source string // 取得元 (TitleSourceTitle など)
+	Value  string // 取得元から得られた値 (サイト名の除去前)
+	Chosen bool   // ページタイトルとして採用された場合は true
+	Reason string // 採用された理由。採用されなかった候補では空文字列です。
+}
+
+// titleChoice は、設定された優先順位に従って選ばれたタイトルとその経緯です。
+type titleChoice struct {
+	title    string
+	source   string // 採用した取得元。どの取得元からも値が得られなかった場合は空文字列
+	rank     int    // 採用した取得元の優先順位 (1始まり)
+	stripped bool   // サイト名の接尾辞を除去した場合は true
+}
+
+// findTitle は設定された優先順位に従ってページタイトルを取得します。
+func (e *Extractor) findTitle(doc *goquery.Document) string {
+	return e.chooseTitle(doc).title
+}
+
+// chooseTitle は、設定された優先順位に従って最初に空でない値が得られた取得元を採用します。
+// 未知の取得元は無視します。
+func (e *Extractor) chooseTitle(doc *goquery.Document) titleChoice {
+	sources := e.titleSources
+	if len(sources) == 0 {
+		sources = defaultTitleSources
+	}
+
+	var choice titleChoice
+	for i, source := range sources {
+		if title := titleFromSource(doc, source); title != "" {
+			choice = titleChoice{title: title, source: strings.ToLower(strings.TrimSpace(source)), rank: i + 1}
+			break
+		}
+	}
+
+	if e.stripSiteSuffix {
+		stripped := stripSiteSuffix(choice.title, metaContent(doc, "og:site_name"))
+		choice.stripped = stripped != choice.title
+		choice.title = stripped
+	}
+	return choice
+}
+
+// titleCandidates は、すべての取得元から得られた値と、採用された候補およびその理由を返します。
+// JSON-LD の候補は、解析済みの article (該当が無い場合は nil) の headline を使用します。
+func (e *Extractor) titleCandidates(doc *goquery.Document, article *JSONLDArticle) []TitleCandidate {
+	choice := e.chooseTitle(doc)
+
+	candidates := make([]TitleCandidate, 0, len(allTitleSources))
+	for _, source := range allTitleSources {
+		candidate := TitleCandidate{Source: source}
+		if source != TitleSourceJSONLD {
+			candidate.Value = titleFromSource(doc, source)
+		} else if article != nil {
+			candidate.Value = article.Headline
+		}
+		if source == choice.source {
+			candidate.Chosen = true
+			candidate.Reason = fmt.Sprintf("優先順位 %d 番目の取得元で最初に値が得られました", choice.rank)
+			if choice.stripped {
+				candidate.Reason += " (サイト名の接尾辞を除去)"
+			}
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates
+}
+
+// titleFromSource は単一の取得元からタイトルを読み取ります。
+func titleFromSource(doc *goquery.Document, source string) string {
+	switch strings.ToLower(strings.TrimSpace(source)) {
+	case TitleSourceOGTitle:
+		return metaContent(doc, "og:title")
+	case TitleSourceTwitterTitle:
+		return metaContent(doc, "twitter:title")
+	case TitleSourceTitle:
+		return strings.TrimSpace(doc.Find("title").First().Text())
+	case TitleSourceH1:
+		return collapseSpaces(doc.Find("h1").First().Text())
+	case TitleSourceJSONLD:
+		if article := findJSONLDArticle(jsonLDObjects(doc)); article != nil {
+			return article.Headline
+		}
+		return ""
+	default:
+		return ""
+	}
+}
+
+// stripSiteSuffix は "記事名 - サイト名" のような末尾のサイト名を取り除きます。
+// サイト名が不明な場合や、取り除くとタイトルが空になる場合はそのまま返します。
+func stripSiteSuffix(title, siteName string) string {
+	if siteName == "" {
+		return title
+	}
+	for _, sep := range siteSuffixSeparators {
+		if trimmed, ok := strings.CutSuffix(title, sep+siteName); ok {
+			if trimmed = strings.TrimSpace(trimmed); trimmed != "" {
+				return trimmed
+			}
+		}
+	}
+	return title
+}
@@ -0,0 +1,39 @@
+package extract
+
+import (
+	"bytes"
+	"io"
+	"unicode/utf8"
+)
+
+// limitHTML は reader から最大 limit バイトを読み込み、超過した場合は安全な位置で切り詰めます。
+// 切り詰めはタグの途中やマルチバイト文字の途中を避けて行われます。
+func limitHTML(reader io.Reader, limit int) (io.Reader, error) {
+	data, err := io.ReadAll(io.LimitReader(reader, int64(limit)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) <= limit {
+		return bytes.NewReader(data), nil
+	}
+	return bytes.NewReader(truncateHTML(data[:limit])), nil
+}
+
+// truncateHTML は切り詰めたHTMLの末尾を整えます。
+// 閉じていないタグが末尾に残る場合はその開始位置で、そうでなければ
+// 不完全なUTF-8シーケンスの手前で切り詰めます。
+func truncateHTML(data []byte) []byte {
+	if lt := bytes.LastIndexByte(data, '<'); lt > bytes.LastIndexByte(data, '>') {
+		return data[:lt]
+	}
+
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				return data[:i]
+			}
+			break
+		}
+	}
+	return data
+}
@@ -0,0 +1,55 @@
+package extract
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// parseBaseURL はページURLを相対URL解決の基準として解析します。
+// 絶対URLとして解釈できない場合は nil を返します。
+func parseBaseURL(pageURL string) *url.URL {
+	base, err := url.Parse(strings.TrimSpace(pageURL))
+	if err != nil || !base.IsAbs() || base.Host == "" {
+		return nil
+	}
+	return base
+}
+
+// documentBaseURL は、ドキュメント内の相対URLを解決する基準URLを返します。
+// head 内の base 要素の href が宣言されている場合はページURLに対して解決した値を優先し、
+// 宣言が無い場合はページURLを返します。いずれも絶対URLとして解釈できない場合は nil を返します。
+func documentBaseURL(doc *goquery.Document, pageURL string) *url.URL {
+	page := parseBaseURL(pageURL)
+
+	href := strings.TrimSpace(doc.Find("base[href]").First().AttrOr("href", ""))
+	if href == "" {
+		return page
+	}
+	if base := parseBaseURL(resolveURL(page, href)); base != nil {
+		return base
+	}
+	return page
+}
+
+// resolveURL は ref を base に対して解決し、絶対URLを返します。
+// "//cdn.example.com/a.png" のようなプロトコル相対URLには base のスキームを補います。
+// 解決できない場合は空文字列を返します。
+func resolveURL(base *url.URL, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return ""
+	}
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	if refURL.IsAbs() {
+		return refURL.String()
+	}
+	if base == nil {
+		return ""
+	}
+	return base.ResolveReference(refURL).String()
+}
@@ -0,0 +1,124 @@
+package fetcher
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/shouni/go-web-exact/v2/ports"
+	"github.com/shouni/go-web-exact/v2/urlutil"
+)
+
+// CachingFetcher は、任意の ports.Fetcher をディスクキャッシュで包むデコレーターです。
+// 取得したバイト列を正規化したURLのハッシュをファイル名として保存し、プロセスを再起動しても再利用します。
+// 抽出ルールの開発中など、同じページを繰り返し処理する場合の通信を削減します。
+// キャッシュキーはURLのみから生成するため、ports.URLSpec のヘッダーや User-Agent が異なる取得も
+// 同じエントリを共有します。設定によって応答が変わるURLには使用しないでください。
+type CachingFetcher struct {
+	inner ports.Fetcher
+	dir   string
+	ttl   time.Duration
+	locks sync.Map // キャッシュキーごとの *sync.Mutex
+}
+
+// NewCachingFetcher は CachingFetcher を生成します。
+// キャッシュは dir 配下に保存され、ttl を過ぎたエントリは再取得されます。ttl が 0 以下の場合は期限切れになりません。
+func NewCachingFetcher(inner ports.Fetcher, dir string, ttl time.Duration) (*CachingFetcher, error) {
+	if inner == nil {
+		return nil, fmt.Errorf("fetcher.NewCachingFetcher: Fetcher cannot be nil")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("キャッシュディレクトリの作成に失敗しました: %w", err)
+	}
+	return &CachingFetcher{
+		inner: inner,
+		dir:   dir,
+		ttl:   ttl,
+	}, nil
+}
+
+// FetchBytes は有効なキャッシュがあればその内容を返し、無ければ内部の Fetcher で取得して保存します。
+func (f *CachingFetcher) FetchBytes(ctx context.Context, url string) ([]byte, error) {
+	key := cacheKey(url)
+
+	// 同じURLへの同時リクエストで重複取得しないよう、キーごとに直列化します
+	mu, _ := f.locks.LoadOrStore(key, &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	defer mu.(*sync.Mutex).Unlock()
+
+	path := filepath.Join(f.dir, key)
+	if data, ok := f.readFresh(path); ok {
+		return data, nil
+	}
+
+	data, err := f.inner.FetchBytes(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeFileAtomic(f.dir, path, data); err != nil {
+		return nil, fmt.Errorf("キャッシュの書き込みに失敗しました: %w", err)
+	}
+	return data, nil
+}
+
+// Close は、内部の Fetcher が io.Closer を実装している場合にそのリソースを解放します。
+func (f *CachingFetcher) Close() error {
+	if closer, ok := f.inner.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// readFresh は期限内のキャッシュファイルを読み込みます。
+func (f *CachingFetcher) readFresh(path string) ([]byte, bool) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, false
+	}
+	if f.ttl > 0 && time.Since(info.ModTime()) > f.ttl {
+		return nil, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// cacheKey はURLからキャッシュファイル名を生成します。
+// URLは urlutil.Canonicalize で正規化し、正規化できない場合はそのまま使用します。
+// 相関IDなど取得ごとに変わるヘッダーでキャッシュが無効にならないよう、ports.URLSpec の設定はキーに含めません。
+func cacheKey(url string) string {
+	if canonical, err := urlutil.Canonicalize(url); err == nil {
+		url = canonical
+	}
+	sum := sha256.Sum256([]byte(url))
+	return hex.EncodeToString(sum[:])
+}
+
+// writeFileAtomic は一時ファイルに書き込んでからリネームすることで、
+// 読み込み側が書きかけのファイルを参照しないようにします。
+func writeFileAtomic(dir, path string, data []byte) error {
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	// リネームに成功した場合は一時ファイルが存在しないため、削除エラーは無視します
+	defer func() { _ = os.Remove(tmpName) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpName, path)
+}
@@ -0,0 +1,138 @@
+package fetcher_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shouni/go-web-exact/v2/fetcher"
+	"github.com/shouni/go-web-exact/v2/ports"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ ports.Fetcher = (*fetcher.CachingFetcher)(nil)
+
+// countingFetcher は呼び出し回数を記録するテスト用 Fetcher です。
+type countingFetcher struct {
+	calls int32
+	err   error
+}
+
+func (c *countingFetcher) FetchBytes(ctx context.Context, url string) ([]byte, error) {
+	n := atomic.AddInt32(&c.calls, 1)
+	if c.err != nil {
+		return nil, c.err
+	}
+	return []byte(url + "#" + string(rune('0'+n))), nil
+}
+
+func TestCachingFetcher(t *testing.T) {
+	t.Run("cache_hit_skips_inner_fetcher", func(t *testing.T) {
+		inner := &countingFetcher{}
+		f, err := fetcher.NewCachingFetcher(inner, t.TempDir(), time.Hour)
+		assert.NoError(t, err)
+
+		first, err := f.FetchBytes(context.Background(), "https://example.com/a")
+		assert.NoError(t, err)
+		second, err := f.FetchBytes(context.Background(), "https://example.com/a")
+		assert.NoError(t, err)
+
+		assert.Equal(t, first, second)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&inner.calls))
+	})
+
+	t.Run("cache_survives_new_instance", func(t *testing.T) {
+		dir := t.TempDir()
+		inner := &countingFetcher{}
+		f1, err := fetcher.NewCachingFetcher(inner, dir, time.Hour)
+		assert.NoError(t, err)
+		_, err = f1.FetchBytes(context.Background(), "https://example.com/a")
+		assert.NoError(t, err)
+
+		f2, err := fetcher.NewCachingFetcher(inner, dir, time.Hour)
+		assert.NoError(t, err)
+		_, err = f2.FetchBytes(context.Background(), "https://example.com/a")
+		assert.NoError(t, err)
+
+		assert.Equal(t, int32(1), atomic.LoadInt32(&inner.calls))
+	})
+
+	t.Run("stale_entry_is_refetched", func(t *testing.T) {
+		dir := t.TempDir()
+		inner := &countingFetcher{}
+		f, err := fetcher.NewCachingFetcher(inner, dir, time.Minute)
+		assert.NoError(t, err)
+
+		_, err = f.FetchBytes(context.Background(), "https://example.com/a")
+		assert.NoError(t, err)
+
+		// キャッシュファイルの更新日時を過去にずらして期限切れにします
+		entries, err := os.ReadDir(dir)
+		assert.NoError(t, err)
+		assert.Len(t, entries, 1)
+		old := time.Now().Add(-time.Hour)
+		assert.NoError(t, os.Chtimes(filepath.Join(dir, entries[0].Name()), old, old))
+
+		data, err := f.FetchBytes(context.Background(), "https://example.com/a")
+		assert.NoError(t, err)
+
+		assert.Equal(t, "https://example.com/a#2", string(data))
+		assert.Equal(t, int32(2), atomic.LoadInt32(&inner.calls))
+	})
+
+	t.Run("errors_are_not_cached", func(t *testing.T) {
+		inner := &countingFetcher{err: errors.New("timeout")}
+		f, err := fetcher.NewCachingFetcher(inner, t.TempDir(), time.Hour)
+		assert.NoError(t, err)
+
+		for range 2 {
+			_, err := f.FetchBytes(context.Background(), "https://example.com/a")
+			assert.Error(t, err)
+		}
+		assert.Equal(t, int32(2), atomic.LoadInt32(&inner.calls))
+	})
+
+	t.Run("concurrent_requests_fetch_once", func(t *testing.T) {
+		inner := &countingFetcher{}
+		f, err := fetcher.NewCachingFetcher(inner, t.TempDir(), time.Hour)
+		assert.NoError(t, err)
+
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := f.FetchBytes(context.Background(), "https://example.com/a")
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), atomic.LoadInt32(&inner.calls))
+	})
+
+	t.Run("nil_inner_fetcher", func(t *testing.T) {
+		f, err := fetcher.NewCachingFetcher(nil, t.TempDir(), time.Hour)
+
+		assert.Error(t, err)
+		assert.Nil(t, f)
+	})
+}
+
+func TestCachingFetcher_CanonicalKey(t *testing.T) {
+	inner := &countingFetcher{}
+	f, err := fetcher.NewCachingFetcher(inner, t.TempDir(), time.Hour)
+	assert.NoError(t, err)
+
+	_, err = f.FetchBytes(context.Background(), "https://example.com/a?utm_source=feed")
+	assert.NoError(t, err)
+	_, err = f.FetchBytes(context.Background(), "https://EXAMPLE.com/a#top")
+	assert.NoError(t, err)
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&inner.calls))
+}
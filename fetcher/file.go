@@ -0,0 +1,55 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// FileFetcher は、ローカルファイルからHTMLを読み込む ports.Fetcher の実装です。
+// "file:///path/to/page.html" 形式のURL、またはファイルパスをそのまま受け付けます。
+type FileFetcher struct{}
+
+// NewFileFetcher は FileFetcher を生成します。
+func NewFileFetcher() *FileFetcher {
+	return &FileFetcher{}
+}
+
+// FetchBytes は指定されたファイルの内容を読み込みます。
+func (f *FileFetcher) FetchBytes(ctx context.Context, rawURL string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	path, err := filePath(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("ファイルの読み込みに失敗しました: %w", err)
+	}
+	return data, nil
+}
+
+// filePath は file スキームのURLまたはファイルパスから、読み込むファイルのパスを返します。
+func filePath(rawURL string) (string, error) {
+	if !strings.Contains(rawURL, "://") {
+		return rawURL, nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("URLの解析に失敗しました: %w", err)
+	}
+	if u.Scheme != "file" {
+		return "", fmt.Errorf("FileFetcher は %s スキームに対応していません: %s", u.Scheme, rawURL)
+	}
+	if u.Host != "" && u.Host != "localhost" {
+		return "", fmt.Errorf("リモートホストのファイルは読み込めません: %s", rawURL)
+	}
+	return u.Path, nil
+}
@@ -0,0 +1,62 @@
+package fetcher_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shouni/go-web-exact/v2/extract"
+	"github.com/shouni/go-web-exact/v2/fetcher"
+	"github.com/shouni/go-web-exact/v2/ports"
+	"github.com/stretchr/testify/assert"
+)
+
+// FileFetcher が ports.Fetcher を満たすことをコンパイル時に検証します。
+var _ ports.Fetcher = (*fetcher.FileFetcher)(nil)
+
+func TestFileFetcher_FetchBytes(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "page.html")
+	html := `<html><head><title>Local</title></head><body><main><p>This paragraph was read from a local file on disk.</p></main></body></html>`
+	assert.NoError(t, os.WriteFile(path, []byte(html), 0o644))
+
+	f := fetcher.NewFileFetcher()
+
+	t.Run("file_url", func(t *testing.T) {
+		data, err := f.FetchBytes(context.Background(), "file://"+filepath.ToSlash(path))
+
+		assert.NoError(t, err)
+		assert.Equal(t, html, string(data))
+	})
+
+	t.Run("plain_path", func(t *testing.T) {
+		data, err := f.FetchBytes(context.Background(), path)
+
+		assert.NoError(t, err)
+		assert.Equal(t, html, string(data))
+	})
+
+	t.Run("unsupported_scheme", func(t *testing.T) {
+		_, err := f.FetchBytes(context.Background(), "https://example.com/page.html")
+
+		assert.Error(t, err)
+	})
+
+	t.Run("missing_file", func(t *testing.T) {
+		_, err := f.FetchBytes(context.Background(), filepath.Join(dir, "missing.html"))
+
+		assert.Error(t, err)
+	})
+
+	t.Run("works_with_extractor", func(t *testing.T) {
+		extractor, err := extract.NewExtractor(f)
+		assert.NoError(t, err)
+
+		text, hasBody, err := extractor.FetchAndExtractText(context.Background(), path)
+
+		assert.NoError(t, err)
+		assert.True(t, hasBody)
+		assert.Equal(t, "【記事タイトル】 Local\n\nThis paragraph was read from a local file on disk.", text)
+	})
+}
@@ -0,0 +1,67 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// ObjectGetter は、オブジェクトストレージからオブジェクトを取得するクライアントのインターフェースです。
+// AWS SDK などのクライアントをこのインターフェースに適合させて注入することで、
+// このパッケージが特定のSDKに依存しないようにしています。
+type ObjectGetter interface {
+	GetObject(ctx context.Context, bucket, key string) (io.ReadCloser, error)
+}
+
+// ObjectStoreFetcher は、"s3://bucket/key" 形式のURLで指定されたオブジェクトを読み込む
+// ports.Fetcher の実装です。アーカイブ済みのHTMLを既存の Extractor やスクレイパーで処理できます。
+type ObjectStoreFetcher struct {
+	client ObjectGetter
+}
+
+// NewObjectStoreFetcher は ObjectStoreFetcher を生成します。
+func NewObjectStoreFetcher(client ObjectGetter) (*ObjectStoreFetcher, error) {
+	if client == nil {
+		return nil, fmt.Errorf("fetcher.NewObjectStoreFetcher: ObjectGetter cannot be nil")
+	}
+	return &ObjectStoreFetcher{client: client}, nil
+}
+
+// FetchBytes は指定されたオブジェクトの内容をダウンロードします。
+func (f *ObjectStoreFetcher) FetchBytes(ctx context.Context, rawURL string) ([]byte, error) {
+	bucket, key, err := parseObjectURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := f.client.GetObject(ctx, bucket, key)
+	if err != nil {
+		return nil, fmt.Errorf("オブジェクトの取得に失敗しました (bucket=%s, key=%s): %w", bucket, key, err)
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("オブジェクトの読み込みに失敗しました (bucket=%s, key=%s): %w", bucket, key, err)
+	}
+	return data, nil
+}
+
+// parseObjectURL は "s3://bucket/key" 形式のURLをバケット名とキーに分解します。
+func parseObjectURL(rawURL string) (bucket, key string, err error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", fmt.Errorf("URLの解析に失敗しました: %w", err)
+	}
+	if u.Scheme != "s3" {
+		return "", "", fmt.Errorf("ObjectStoreFetcher は %s スキームに対応していません: %s", u.Scheme, rawURL)
+	}
+
+	key = strings.TrimPrefix(u.Path, "/")
+	if u.Host == "" || key == "" {
+		return "", "", fmt.Errorf("バケット名とキーを含むURLを指定してください: %s", rawURL)
+	}
+	return u.Host, key, nil
+}
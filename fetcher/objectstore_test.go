@@ -0,0 +1,63 @@
+package fetcher_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/shouni/go-web-exact/v2/fetcher"
+	"github.com/shouni/go-web-exact/v2/ports"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ ports.Fetcher = (*fetcher.ObjectStoreFetcher)(nil)
+
+// mockObjectGetter はバケットとキーの組み合わせごとにオブジェクトを返すモックです。
+type mockObjectGetter struct {
+	objects map[string]string
+}
+
+func (m *mockObjectGetter) GetObject(ctx context.Context, bucket, key string) (io.ReadCloser, error) {
+	body, ok := m.objects[bucket+"/"+key]
+	if !ok {
+		return nil, errors.New("NoSuchKey")
+	}
+	return io.NopCloser(strings.NewReader(body)), nil
+}
+
+func TestObjectStoreFetcher_FetchBytes(t *testing.T) {
+	client := &mockObjectGetter{objects: map[string]string{
+		"archive/2024/05/page.html": "<html>archived</html>",
+	}}
+	f, err := fetcher.NewObjectStoreFetcher(client)
+	assert.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		data, err := f.FetchBytes(context.Background(), "s3://archive/2024/05/page.html")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "<html>archived</html>", string(data))
+	})
+
+	t.Run("missing_object", func(t *testing.T) {
+		_, err := f.FetchBytes(context.Background(), "s3://archive/missing.html")
+
+		assert.ErrorContains(t, err, "NoSuchKey")
+	})
+
+	t.Run("invalid_urls", func(t *testing.T) {
+		for _, rawURL := range []string{"https://archive/page.html", "s3://archive/", "s3:///page.html"} {
+			_, err := f.FetchBytes(context.Background(), rawURL)
+			assert.Error(t, err, rawURL)
+		}
+	})
+
+	t.Run("nil_client", func(t *testing.T) {
+		f, err := fetcher.NewObjectStoreFetcher(nil)
+
+		assert.Error(t, err)
+		assert.Nil(t, f)
+	})
+}
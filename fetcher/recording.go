@@ -0,0 +1,56 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/shouni/go-web-exact/v2/ports"
+)
+
+// RecordingFetcher は、任意の ports.Fetcher による取得ごとに ports.FetchRecorder へ監査記録を渡すデコレーターです。
+// Extractor にこの Fetcher を注入すると、スクレイパーによる一括実行で行われたすべての取得
+// (iframe の取得を含む) が記録されます。
+// HTTP ステータスやリトライ回数は内部の Fetcher の責務のため、エラーの内容として記録されます。
+type RecordingFetcher struct {
+	inner    ports.Fetcher
+	recorder ports.FetchRecorder
+}
+
+// NewRecordingFetcher は RecordingFetcher を生成します。recorder が nil の場合は記録を行いません。
+func NewRecordingFetcher(inner ports.Fetcher, recorder ports.FetchRecorder) (*RecordingFetcher, error) {
+	if inner == nil {
+		return nil, fmt.Errorf("fetcher.NewRecordingFetcher: Fetcher cannot be nil")
+	}
+	return &RecordingFetcher{
+		inner:    inner,
+		recorder: recorder,
+	}, nil
+}
+
+// FetchBytes は内部の Fetcher で取得し、その結果を記録します。
+func (f *RecordingFetcher) FetchBytes(ctx context.Context, url string) ([]byte, error) {
+	if f.recorder == nil {
+		return f.inner.FetchBytes(ctx, url)
+	}
+
+	start := time.Now()
+	data, err := f.inner.FetchBytes(ctx, url)
+	f.recorder.RecordFetch(ports.FetchRecord{
+		URL:       url,
+		StartedAt: start,
+		Duration:  time.Since(start),
+		Bytes:     len(data),
+		Err:       err,
+	})
+	return data, err
+}
+
+// Close は、内部の Fetcher が io.Closer を実装している場合にそのリソースを解放します。
+func (f *RecordingFetcher) Close() error {
+	if closer, ok := f.inner.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package fetcher_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/shouni/go-web-exact/v2/fetcher"
+	"github.com/shouni/go-web-exact/v2/ports"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ ports.Fetcher = (*fetcher.RecordingFetcher)(nil)
+
+// ledger は記録をメモリに蓄積するテスト用 FetchRecorder です。
+type ledger struct {
+	mu      sync.Mutex
+	records []ports.FetchRecord
+}
+
+func (l *ledger) RecordFetch(record ports.FetchRecord) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.records = append(l.records, record)
+}
+
+// staticFetcher は URL ごとに固定の結果を返すテスト用 Fetcher です。
+type staticFetcher map[string]string
+
+func (s staticFetcher) FetchBytes(ctx context.Context, url string) ([]byte, error) {
+	body, ok := s[url]
+	if !ok {
+		return nil, errors.New("status 404")
+	}
+	return []byte(body), nil
+}
+
+func TestRecordingFetcher(t *testing.T) {
+	t.Run("records_every_fetch", func(t *testing.T) {
+		l := &ledger{}
+		f, err := fetcher.NewRecordingFetcher(staticFetcher{"https://example.com/a": "hello"}, l)
+		assert.NoError(t, err)
+
+		data, err := f.FetchBytes(context.Background(), "https://example.com/a")
+		assert.NoError(t, err)
+		assert.Equal(t, "hello", string(data))
+
+		_, err = f.FetchBytes(context.Background(), "https://example.com/missing")
+		assert.Error(t, err)
+
+		assert.Len(t, l.records, 2)
+		assert.Equal(t, "https://example.com/a", l.records[0].URL)
+		assert.Equal(t, 5, l.records[0].Bytes)
+		assert.NoError(t, l.records[0].Err)
+		assert.False(t, l.records[0].StartedAt.IsZero())
+		assert.Equal(t, "https://example.com/missing", l.records[1].URL)
+		assert.Zero(t, l.records[1].Bytes)
+		assert.Error(t, l.records[1].Err)
+	})
+
+	t.Run("nil_recorder_is_disabled", func(t *testing.T) {
+		f, err := fetcher.NewRecordingFetcher(staticFetcher{"https://example.com/a": "hello"}, nil)
+		assert.NoError(t, err)
+
+		data, err := f.FetchBytes(context.Background(), "https://example.com/a")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "hello", string(data))
+	})
+
+	t.Run("nil_inner_fetcher", func(t *testing.T) {
+		f, err := fetcher.NewRecordingFetcher(nil, &ledger{})
+
+		assert.Error(t, err)
+		assert.Nil(t, f)
+	})
+}
@@ -0,0 +1,11 @@
+package ports
+
+// CheckpointStore は、一括スクレイピングの進捗を記録するストアのインターフェースです。
+// 中断した実行を再開する際に、完了済みのURLを再取得しないために利用します。
+// 実装は複数のゴルーチンから同時に呼び出されても安全である必要があります。
+type CheckpointStore interface {
+	// Completed は、完了済みとして記録されているURLを返します。
+	Completed() []string
+	// MarkDone は、URLの処理が完了したことを記録します。
+	MarkDone(url string)
+}
@@ -0,0 +1,11 @@
+package ports
+
+import "errors"
+
+// ErrSkipped は、スクレイパーの設定によって処理対象から除外されたURLの結果に設定されるエラーです。
+// ScrapeRunner はこのエラーを持つ結果をリトライしません。
+var ErrSkipped = errors.New("処理対象から除外されました")
+
+// ErrNoContent は、取得には成功したものの、ページから本文 (またはタイトル) を抽出できなかったことを表すエラーです。
+// 取得失敗と内容の無いページを errors.Is で区別できるよう、抽出器とスクレイパーはこのエラーをラップして返します。
+var ErrNoContent = errors.New("webページから何も抽出できませんでした")
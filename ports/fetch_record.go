@@ -0,0 +1,19 @@
+package ports
+
+import "time"
+
+// FetchRecord は、1回の取得 (FetchBytes の呼び出し) の監査記録です。
+type FetchRecord struct {
+	URL       string        // 取得したURL
+	StartedAt time.Time     // 取得を開始した時刻
+	Duration  time.Duration // 取得に要した時間
+	Bytes     int           // 取得したバイト数。失敗した場合は 0
+	Err       error         // 取得に失敗した場合のエラー
+}
+
+// FetchRecorder は、取得のたびに監査記録を受け取るインターフェースです。
+// 集計値を扱うメトリクスとは異なり、どのURLをいつ取得したかを1件ずつ記録する用途を想定しています。
+// 実装は複数のゴルーチンから同時に呼び出されても安全である必要があります。
+type FetchRecorder interface {
+	RecordFetch(record FetchRecord)
+}
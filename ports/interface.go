@@ -18,11 +18,36 @@ type Extractor interface {
 	FetchAndExtractText(ctx context.Context, url string) (string, bool, error)
 }
 
+// ContentTypeExtractor は、Content-Type ヘッダーの charset を考慮して本文テキストを抽出できる Extractor です。
+// 既存の Extractor の実装を壊さないよう、任意で実装するインターフェースとして分けています。
+// ScrapeRunner は Extractor がこのインターフェースを実装している場合にのみ Content-Type を渡します。
+type ContentTypeExtractor interface {
+	Extractor
+	// ExtractTextWithContentType は Content-Type ヘッダーの charset を考慮して本文テキストを抽出します。
+	ExtractTextWithContentType(ctx context.Context, reader io.Reader, contentType string) (string, bool, error)
+}
+
 // Scraper はWebコンテンツの抽出機能を提供するインターフェースです。
 type Scraper interface {
 	Run(ctx context.Context, urls []string) []URLResult
 }
 
+// SuccessRecorder は、スクレイパーの外で抽出に成功したURLを、スクレイパーと同じ進捗ストア
+// (CheckpointStore や SeenStore) に記録できる Scraper です。
+// ScrapeRunner は逐次リトライで成功したURLを、Scraper がこのインターフェースを実装している場合に記録します。
+type SuccessRecorder interface {
+	// RecordSuccess は、URLの抽出に成功したことを記録します。
+	RecordSuccess(url string)
+}
+
+// RequestContextProvider は、URLへのリクエストに使用する Context を生成できる Scraper です。
+// ScrapeRunner は逐次リトライの際、Scraper がこのインターフェースを実装している場合に
+// 生成した Context を Extractor に渡し、初回の取得と同じヘッダーなどを Fetcher に引き渡します。
+type RequestContextProvider interface {
+	// RequestContext は、url へのリクエスト設定を格納した Context を返します。
+	RequestContext(ctx context.Context, url string) context.Context
+}
+
 // ScrapeRunner は、スクレイピングの実行パイプライン（並列処理、リトライ制御など）を管理するインターフェースです。
 type ScrapeRunner interface {
 	Run(ctx context.Context, urls []string) []URLResult
@@ -0,0 +1,26 @@
+package ports
+
+import "context"
+
+// URLSpec は、スクレイピング対象のURLと、そのURLにのみ適用するリクエスト設定を保持します。
+type URLSpec struct {
+	URL       string            // 処理対象のURL
+	Headers   map[string]string // このURLへのリクエストに追加するヘッダー
+	UserAgent string            // このURLへのリクエストで使用するUser-Agent。空の場合はFetcherの既定値を使用します。
+}
+
+type urlSpecKey struct{}
+
+// ContextWithURLSpec は、URL単位のリクエスト設定を格納した Context を返します。
+// これは呼び出し側が用意する Fetcher との取り決めで、設定を反映するかは Fetcher の実装に委ねられます。
+// このモジュールの Fetcher は設定を読み取らないため、反映する Fetcher は URLSpecFromContext で設定を取り出してください。
+func ContextWithURLSpec(ctx context.Context, spec URLSpec) context.Context {
+	return context.WithValue(ctx, urlSpecKey{}, spec)
+}
+
+// URLSpecFromContext は、Context に格納されたURL単位のリクエスト設定を返します。
+// 設定が存在しない場合、第2戻り値は false になります。
+func URLSpecFromContext(ctx context.Context) (URLSpec, bool) {
+	spec, ok := ctx.Value(urlSpecKey{}).(URLSpec)
+	return spec, ok
+}
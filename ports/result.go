@@ -1,9 +1,13 @@
 package ports
 
+import "time"
+
 // URLResult は、特定のURLから抽出された結果、またはその処理中に発生したエラーを保持します。
 type URLResult struct {
-	URL         string // 処理対象のURL
-	Content     string // 抽出された記事の本文（または中間処理の結果）
-	ContentType string // HTTPレスポンスのContent-Type。HTML判定に使用されます。
-	Error       error  // 処理中に発生したエラー
+	URL         string        // 処理対象のURL
+	Content     string        // 抽出された記事の本文（または中間処理の結果）
+	ContentType string        // HTTPレスポンスのContent-Type。HTML判定に使用されます。
+	Duration    time.Duration // 取得と抽出に要した時間。レート制限の待機時間は含みません。
+	Slow        bool          // Duration がスクレイパーに設定された閾値を超えた場合に true
+	Error       error         // 処理中に発生したエラー
 }
@@ -0,0 +1,11 @@
+package ports
+
+// SeenStore は、抽出済みのURLを記録するストアのインターフェースです。
+// 複数回の実行にまたがる増分クロールで、処理済みのURLを再取得しないために利用します。
+// 実装は複数のゴルーチンから同時に呼び出されても安全である必要があります。
+type SeenStore interface {
+	// Seen は、URLが既に抽出済みとして記録されている場合に true を返します。
+	Seen(url string) bool
+	// Mark は、URLを抽出済みとして記録します。
+	Mark(url string)
+}
@@ -2,6 +2,7 @@ package runner
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"mime"
@@ -124,13 +125,13 @@ func (r *ScrapeRunner) extractHTMLResults(ctx context.Context, results []ports.U
 					continue
 				}
 
-				content, hasBody, err := r.extractor.ExtractText(ctx, strings.NewReader(res.Content))
+				content, hasBody, err := r.extractHTML(ctx, res)
 				if err != nil {
 					extracted[i].Error = fmt.Errorf("HTML解析失敗: %w", err)
 					continue
 				}
 				if content == "" || !hasBody {
-					extracted[i].Error = fmt.Errorf("URL %s から有効な本文を検出できませんでした", res.URL)
+					extracted[i].Error = fmt.Errorf("URL %s から有効な本文を検出できませんでした: %w", res.URL, ports.ErrNoContent)
 					continue
 				}
 				extracted[i].Content = content
@@ -157,6 +158,17 @@ func (r *ScrapeRunner) extractHTMLResults(ctx context.Context, results []ports.U
 	return extracted
 }
 
+// extractHTML は取得済みのHTMLから本文を抽出します。
+// Extractor が ports.ContentTypeExtractor を実装している場合は Content-Type の charset を考慮し、
+// 実装していない場合は ExtractText にフォールバックします。
+func (r *ScrapeRunner) extractHTML(ctx context.Context, res ports.URLResult) (string, bool, error) {
+	reader := strings.NewReader(res.Content)
+	if extractor, ok := r.extractor.(ports.ContentTypeExtractor); ok {
+		return extractor.ExtractTextWithContentType(ctx, reader, res.ContentType)
+	}
+	return r.extractor.ExtractText(ctx, reader)
+}
+
 // retry は、失敗したURLに対して逐次抽出を試みます。
 func (r *ScrapeRunner) retry(ctx context.Context, urls []string) []ports.URLResult {
 	slog.Warn("抽出失敗URLのリトライ準備中...",
@@ -179,13 +191,13 @@ func (r *ScrapeRunner) retry(ctx context.Context, urls []string) []ports.URLResu
 		default:
 			slog.Info("逐次リトライ中", slog.String("url", url))
 
-			content, hasBody, err := r.extractor.FetchAndExtractText(ctx, url)
+			content, hasBody, err := r.extractor.FetchAndExtractText(r.requestContext(ctx, url), url)
 
 			var extractErr error
 			if err != nil {
 				extractErr = fmt.Errorf("リトライ抽出失敗: %w", err)
 			} else if content == "" || !hasBody {
-				extractErr = fmt.Errorf("URL %s から有効な本文を検出できませんでした", url)
+				extractErr = fmt.Errorf("URL %s から有効な本文を検出できませんでした: %w", url, ports.ErrNoContent)
 			}
 
 			if extractErr != nil {
@@ -196,6 +208,10 @@ func (r *ScrapeRunner) retry(ctx context.Context, urls []string) []ports.URLResu
 			}
 
 			slog.Info("リトライ成功", slog.String("url", url))
+			// 再開時に再取得しないよう、初回の成功と同じくスクレイパーの進捗ストアに記録します
+			if recorder, ok := r.scraper.(ports.SuccessRecorder); ok {
+				recorder.RecordSuccess(url)
+			}
 			results = append(results, ports.URLResult{
 				URL:     url,
 				Content: content,
@@ -205,6 +221,15 @@ func (r *ScrapeRunner) retry(ctx context.Context, urls []string) []ports.URLResu
 	return results
 }
 
+// requestContext は、逐次リトライで使用する Context を返します。
+// WithContextHeader などのリクエスト設定が失われないよう、可能であればスクレイパーに生成を委ねます。
+func (r *ScrapeRunner) requestContext(ctx context.Context, url string) context.Context {
+	if provider, ok := r.scraper.(ports.RequestContextProvider); ok {
+		return provider.RequestContext(ctx, url)
+	}
+	return ctx
+}
+
 // isHTMLContentType はContent-TypeがHTMLとして解析可能かを判定します。
 func isHTMLContentType(contentType string) bool {
 	if contentType == "" {
@@ -240,8 +265,12 @@ func (r *ScrapeRunner) wait(ctx context.Context, d time.Duration) error {
 }
 
 // splitResults は結果を成功と失敗したURLリストに分離します。
+// スクレイパーの設定で除外された (ports.ErrSkipped) URLはリトライ対象に含めません。
 func splitResults(results []ports.URLResult) (successes []ports.URLResult, failed []string) {
 	for _, res := range results {
+		if errors.Is(res.Error, ports.ErrSkipped) {
+			continue
+		}
 		if res.Error != nil || res.Content == "" {
 			failed = append(failed, res.URL)
 		} else {
@@ -3,12 +3,14 @@ package runner
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/shouni/go-web-exact/v2/ports"
+	"github.com/shouni/go-web-exact/v2/scraper"
 )
 
 // mockScraper は ports.Scraper のモックなのだ
@@ -20,6 +22,16 @@ func (m *mockScraper) Run(ctx context.Context, urls []string) []ports.URLResult
 	return m.runFunc(ctx, urls)
 }
 
+// recordingScraper は ports.SuccessRecorder を実装するモックなのだ
+type recordingScraper struct {
+	mockScraper
+	recorded []string
+}
+
+func (m *recordingScraper) RecordSuccess(url string) {
+	m.recorded = append(m.recorded, url)
+}
+
 // mockExtractor は ports.Extractor のモックなのだ
 type mockExtractor struct {
 	extractFunc       func(ctx context.Context, url string) (string, bool, error)
@@ -37,6 +49,17 @@ func (m *mockExtractor) ExtractText(ctx context.Context, reader io.Reader) (stri
 	return "", false, errors.New("unexpected ExtractText call")
 }
 
+// contentTypeExtractor は ports.ContentTypeExtractor を実装するモックなのだ
+type contentTypeExtractor struct {
+	mockExtractor
+	contentTypes []string
+}
+
+func (m *contentTypeExtractor) ExtractTextWithContentType(ctx context.Context, reader io.Reader, contentType string) (string, bool, error) {
+	m.contentTypes = append(m.contentTypes, contentType)
+	return m.ExtractText(ctx, reader)
+}
+
 func TestScrapeRunner_Run(t *testing.T) {
 	// 共通設定: テストを高速化するためにディレイを最小にするのだ！
 	fastOpts := []Option{
@@ -87,6 +110,62 @@ func TestScrapeRunner_Run(t *testing.T) {
 		}
 	})
 
+	t.Run("リトライで成功したURLはスクレイパーの進捗ストアに記録する", func(t *testing.T) {
+		scraper := &recordingScraper{mockScraper: mockScraper{
+			runFunc: func(ctx context.Context, urls []string) []ports.URLResult {
+				return []ports.URLResult{
+					{URL: "http://ok.com", Content: "body_ok"},
+					{URL: "http://retry.com", Error: errors.New("temporary error")},
+					{URL: "http://broken.com", Error: errors.New("temporary error")},
+				}
+			},
+		}}
+		extractor := &mockExtractor{
+			extractFunc: func(ctx context.Context, url string) (string, bool, error) {
+				if url == "http://retry.com" {
+					return "body_retried", true, nil
+				}
+				return "", false, errors.New("still failing")
+			},
+		}
+
+		r := NewScrapeRunner(scraper, extractor, fastOpts...)
+		r.Run(context.Background(), []string{"http://ok.com", "http://retry.com", "http://broken.com"})
+
+		if len(scraper.recorded) != 1 || scraper.recorded[0] != "http://retry.com" {
+			t.Errorf("リトライで成功したURLのみ記録されるべきなのだ。got: %v", scraper.recorded)
+		}
+	})
+
+	t.Run("リトライでもContextの値がヘッダーとして引き渡される", func(t *testing.T) {
+		type requestIDKey struct{}
+		var retriedHeaders map[string]string
+		calls := 0
+		extractor := &mockExtractor{
+			extractFunc: func(ctx context.Context, url string) (string, bool, error) {
+				calls++
+				if calls == 1 {
+					return "", false, errors.New("temporary error")
+				}
+				spec, _ := ports.URLSpecFromContext(ctx)
+				retriedHeaders = spec.Headers
+				return "body_retried", true, nil
+			},
+		}
+		s := scraper.New(extractor, scraper.WithContextHeader(requestIDKey{}, "X-Request-ID"))
+		ctx := context.WithValue(context.Background(), requestIDKey{}, "req-123")
+
+		r := NewScrapeRunner(s, extractor, fastOpts...)
+		results := r.Run(ctx, []string{"http://retry.com"})
+
+		if len(results) != 1 {
+			t.Fatalf("リトライで1件成功すべきなのだ。got: %d", len(results))
+		}
+		if retriedHeaders["X-Request-ID"] != "req-123" {
+			t.Errorf("リトライの取得にもヘッダーが引き渡されるべきなのだ: %v", retriedHeaders)
+		}
+	})
+
 	t.Run("初回結果がHTMLの場合は本文を解析する", func(t *testing.T) {
 		scraper := &mockScraper{
 			runFunc: func(ctx context.Context, urls []string) []ports.URLResult {
@@ -123,6 +202,31 @@ func TestScrapeRunner_Run(t *testing.T) {
 		}
 	})
 
+	t.Run("ContentTypeExtractor を実装する場合は Content-Type を渡す", func(t *testing.T) {
+		scraper := &mockScraper{
+			runFunc: func(ctx context.Context, urls []string) []ports.URLResult {
+				return []ports.URLResult{
+					{URL: "http://sjis.com", Content: "<html><body><p>body</p></body></html>", ContentType: "text/html; charset=Shift_JIS"},
+				}
+			},
+		}
+		extractor := &contentTypeExtractor{mockExtractor: mockExtractor{
+			extractReaderFunc: func(ctx context.Context, reader io.Reader) (string, bool, error) {
+				return "extracted body", true, nil
+			},
+		}}
+
+		r := NewScrapeRunner(scraper, extractor, fastOpts...)
+		results := r.Run(context.Background(), []string{"http://sjis.com"})
+
+		if len(results) != 1 || results[0].Content != "extracted body" {
+			t.Fatalf("HTML解析後の本文が返るべきなのだ。got: %+v", results)
+		}
+		if len(extractor.contentTypes) != 1 || extractor.contentTypes[0] != "text/html; charset=Shift_JIS" {
+			t.Errorf("Content-Type が渡されるべきなのだ。got: %v", extractor.contentTypes)
+		}
+	})
+
 	t.Run("初回結果がHTML以外の場合は解析しない", func(t *testing.T) {
 		scraper := &mockScraper{
 			runFunc: func(ctx context.Context, urls []string) []ports.URLResult {
@@ -216,6 +320,30 @@ func TestScrapeRunner_Run(t *testing.T) {
 		}
 	})
 
+	t.Run("スクレイパーが除外したURLはリトライしない", func(t *testing.T) {
+		scraper := &mockScraper{
+			runFunc: func(ctx context.Context, urls []string) []ports.URLResult {
+				return []ports.URLResult{
+					{URL: "http://ok.com", Content: "body_ok"},
+					{URL: "http://skipped.com", Error: fmt.Errorf("上限超過: %w", ports.ErrSkipped)},
+				}
+			},
+		}
+		extractor := &mockExtractor{
+			extractFunc: func(ctx context.Context, url string) (string, bool, error) {
+				t.Fatalf("除外されたURLはリトライしないべきなのだ: %s", url)
+				return "", false, nil
+			},
+		}
+
+		r := NewScrapeRunner(scraper, extractor, fastOpts...)
+		results := r.Run(context.Background(), []string{"http://ok.com", "http://skipped.com"})
+
+		if len(results) != 1 {
+			t.Errorf("成功した1件のみ返るべきなのだ。got: %d", len(results))
+		}
+	})
+
 	t.Run("全ての取得に失敗し空のスライスが返る場合", func(t *testing.T) {
 		scraper := &mockScraper{
 			runFunc: func(ctx context.Context, urls []string) []ports.URLResult {
@@ -0,0 +1,83 @@
+package scraper
+
+import (
+	"bufio"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+)
+
+// FileCheckpointStore は、完了済みのURLを1行に1件ずつファイルへ追記する ports.CheckpointStore の実装です。
+// 生成時に既存のファイルを読み込むため、中断した実行を同じファイルで再開できます。
+type FileCheckpointStore struct {
+	mu   sync.Mutex
+	file *os.File
+	done map[string]struct{}
+	urls []string
+}
+
+// NewFileCheckpointStore は path のファイルを開き (存在しない場合は作成し)、記録済みのURLを読み込みます。
+// 使用後は Close を呼び出してファイルを閉じる必要があります。
+func NewFileCheckpointStore(path string) (*FileCheckpointStore, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("チェックポイントファイルを開けませんでした: %w", err)
+	}
+
+	s := &FileCheckpointStore{
+		file: file,
+		done: make(map[string]struct{}),
+	}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		s.add(strings.TrimSpace(scanner.Text()))
+	}
+	if err := scanner.Err(); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("チェックポイントファイルの読み込みに失敗しました: %w", err)
+	}
+	return s, nil
+}
+
+// Completed は、記録済みのURLを記録順に返します。
+func (s *FileCheckpointStore) Completed() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.urls...)
+}
+
+// MarkDone は、URLを完了済みとしてファイルに追記します。記録済みのURLは追記しません。
+// 書き込みに失敗した場合はエラーログを出力し、再開時にそのURLが再取得されます。
+func (s *FileCheckpointStore) MarkDone(url string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.add(url) {
+		return
+	}
+	if _, err := s.file.WriteString(url + "\n"); err != nil {
+		slog.Error("チェックポイントの書き込みに失敗しました", slog.String("url", url), slog.Any("error", err))
+	}
+}
+
+// Close はチェックポイントファイルを閉じます。
+func (s *FileCheckpointStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+// add は、URLが未記録であればメモリ上の記録に追加して true を返します。
+func (s *FileCheckpointStore) add(url string) bool {
+	if url == "" {
+		return false
+	}
+	if _, ok := s.done[url]; ok {
+		return false
+	}
+	s.done[url] = struct{}{}
+	s.urls = append(s.urls, url)
+	return true
+}
@@ -0,0 +1,59 @@
+package scraper
+
+import (
+	"context"
+	"sync"
+)
+
+// hostGate は、同時に処理中となるホストの種類数を制限します。
+// 既に処理中のホストへのリクエストは、上限に関わらず通過させます。
+type hostGate struct {
+	mu      sync.Mutex
+	max     int
+	active  map[string]int
+	changed chan struct{} // 処理中のホストが減るたびに close して待機中のゴルーチンを起こします
+}
+
+// newHostGate は、同時に処理するホスト数を max に制限する hostGate を生成します。
+func newHostGate(max int) *hostGate {
+	return &hostGate{
+		max:     max,
+		active:  make(map[string]int),
+		changed: make(chan struct{}),
+	}
+}
+
+// acquire は、host の処理を開始できるまで待機します。Context の終了を考慮します。
+// 成功した場合、処理の完了後に release を呼び出す必要があります。
+func (g *hostGate) acquire(ctx context.Context, host string) error {
+	for {
+		g.mu.Lock()
+		if g.active[host] > 0 || len(g.active) < g.max {
+			g.active[host]++
+			g.mu.Unlock()
+			return nil
+		}
+		changed := g.changed
+		g.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// release は host の処理の完了を記録します。
+func (g *hostGate) release(host string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.active[host]--
+	if g.active[host] > 0 {
+		return
+	}
+	delete(g.active, host)
+	close(g.changed)
+	g.changed = make(chan struct{})
+}
@@ -1,10 +1,26 @@
 package scraper
 
-import "time"
+import (
+	"time"
+
+	"github.com/shouni/go-web-exact/v2/ports"
+)
 
 // Option はParallelScraperの設定を行うための関数型です。
 type Option func(*Concurrent)
 
+// ResultOrdering は Run が返す結果スライスの並び順を表します。
+type ResultOrdering int
+
+const (
+	// OrderInput は入力されたURLの順に結果を返します (デフォルト)。差分比較に適しています。
+	OrderInput ResultOrdering = iota
+	// OrderCompletion は処理が完了した順に結果を返します。
+	OrderCompletion
+	// OrderScore は品質スコア (抽出した本文の文字数) の高い順に結果を返し、エラーは末尾に並べます。
+	OrderScore
+)
+
 // WithMaxConcurrency は最大並列を設定します。
 func WithMaxConcurrency(max int) Option {
 	return func(c *Concurrent) {
@@ -22,3 +38,106 @@ func WithRateLimit(d time.Duration) Option {
 		}
 	}
 }
+
+// WithFetchDelayJitter は、レートリミッターの待機後に 0 から maxExtra までのランダムな待機を追加します。
+// リクエスト間隔が一定になりすぎることを防ぎます。0 以下の場合はジッターを追加しません。
+func WithFetchDelayJitter(maxExtra time.Duration) Option {
+	return func(c *Concurrent) {
+		if maxExtra > 0 {
+			c.fetchJitter = maxExtra
+		}
+	}
+}
+
+// WithResultCallback は、URLごとの処理が完了するたびに呼び出されるコールバックを設定します。
+// コールバックは直列に呼び出されるため、呼び出し側で排他制御を行う必要はありません。
+func WithResultCallback(fn func(ports.URLResult)) Option {
+	return func(c *Concurrent) {
+		c.onResult = fn
+	}
+}
+
+// WithContextHeader は、Context に格納された値をリクエストヘッダーとして ports.URLSpec に追加します。
+// ヘッダーをリクエストに反映するのは ports.URLSpecFromContext を使用する Fetcher です。
+// X-Request-ID などの相関IDを伝播する用途を想定しています。
+// 値が存在しない、または空の場合はヘッダーを付与しません。
+// ScrapeRunner の逐次リトライでも、RequestContext を通じて同じヘッダーを付与します。
+func WithContextHeader(ctxKey any, headerName string) Option {
+	return func(c *Concurrent) {
+		if ctxKey != nil && headerName != "" {
+			c.contextHeaders = append(c.contextHeaders, contextHeader{key: ctxKey, name: headerName})
+		}
+	}
+}
+
+// WithMaxURLsPerHost は、1回の実行で同一ホストから処理するURLの最大数を設定します。
+// 上限を超えたURLは取得せず、ports.ErrSkipped をラップしたエラーを結果に設定します。
+// レート制限が速度を抑えるのに対し、こちらは総量を抑えます。0 以下の場合は無制限です。
+func WithMaxURLsPerHost(n int) Option {
+	return func(c *Concurrent) {
+		if n > 0 {
+			c.maxURLsPerHost = n
+		}
+	}
+}
+
+// WithResultOrdering は Run が返す結果の並び順を設定します。デフォルトは OrderInput です。
+func WithResultOrdering(ordering ResultOrdering) Option {
+	return func(c *Concurrent) {
+		c.ordering = ordering
+	}
+}
+
+// WithSeenStore は、抽出済みURLを記録するストアを設定します。
+// ストアに記録済みのURLは取得せず、ports.ErrSkipped をラップしたエラーを結果に設定します。
+// 本文の抽出に成功したURLはストアに記録されるため、以降の実行ではスキップされます。
+func WithSeenStore(store ports.SeenStore) Option {
+	return func(c *Concurrent) {
+		c.seen = store
+	}
+}
+
+// WithTreatTitleOnlyAsSuccess は、本文が見つからずタイトルのみを抽出できた結果を成功として扱うかを設定します。
+// 有効な場合、タイトルのみの結果は Content に設定され、Error は nil になります。
+// デフォルトでは本文が無い結果はエラーとして扱います。
+// 抽出器側で extract.WithAllowTitleOnly を有効にした場合は、抽出器がタイトルのみの結果に
+// hasBodyFound = true を返すため、この設定に関わらず成功として扱われます。
+func WithTreatTitleOnlyAsSuccess(enabled bool) Option {
+	return func(c *Concurrent) {
+		c.titleOnlyOK = enabled
+	}
+}
+
+// WithSlowThreshold は、取得と抽出に要した時間がこの閾値を超えたページを低速として記録します。
+// 該当する結果は URLResult.Slow が true になり、警告ログが出力されます。
+// 成功したページも対象になるため、SLA の監視に利用できます。0 以下の場合は判定しません。
+func WithSlowThreshold(d time.Duration) Option {
+	return func(c *Concurrent) {
+		if d > 0 {
+			c.slowThreshold = d
+		}
+	}
+}
+
+// WithMaxConcurrentHosts は、同時に処理するホストの種類数の上限を設定します。
+// 多数のドメインにまたがるURLを処理する際に、同時に開く接続先を抑えます。
+// 全体の同時実行数 (WithMaxConcurrency) とは独立した制限で、処理中のホストのURLは上限に関わらず実行されます。
+// 0 以下の場合は無制限です。
+func WithMaxConcurrentHosts(n int) Option {
+	return func(c *Concurrent) {
+		if n > 0 {
+			c.maxHosts = n
+		}
+	}
+}
+
+// WithCheckpointStore は、一括実行の進捗を記録するストアを設定します。
+// 実行開始時にストアの完了済みURLを取得せずにスキップし (ports.ErrSkipped をラップしたエラーを設定)、
+// 本文の抽出に成功したURLはその都度ストアに記録します。中断した長時間の実行を再開する用途を想定しています。
+// 完了済みかどうかは urlutil.Canonicalize で正規化したURLで比較するため、フラグメントやトラッキングパラメーターのみが
+// 異なるURLもスキップされます。ScrapeRunner の逐次リトライで成功したURLも RecordSuccess を通じて記録されます。
+func WithCheckpointStore(store ports.CheckpointStore) Option {
+	return func(c *Concurrent) {
+		c.checkpoint = store
+	}
+}
@@ -3,7 +3,15 @@ package scraper
 import (
 	"context"
 	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/time/rate"
@@ -18,11 +26,40 @@ const (
 	DefaultRateLimit = 200 * time.Millisecond
 )
 
+// contextHeader は、Context の値をリクエストヘッダーへ写すための設定です。
+type contextHeader struct {
+	key  any
+	name string
+}
+
+// indexedSpec は、入力順のインデックスを伴うURL設定です。
+type indexedSpec struct {
+	index int
+	spec  ports.URLSpec
+}
+
+// indexedResult は、入力順のインデックスを伴う処理結果です。
+type indexedResult struct {
+	index  int
+	result ports.URLResult
+}
+
 // Concurrent は、並列かつレート制限を考慮してスクレイピングを実行するエンジンです。
 type Concurrent struct {
 	extractor      ports.Extractor
 	maxConcurrency int
 	rateLimit      time.Duration
+	fetchJitter    time.Duration
+	maxURLsPerHost int
+	ordering       ResultOrdering
+	contextHeaders []contextHeader
+	seen           ports.SeenStore
+	checkpoint     ports.CheckpointStore
+	titleOnlyOK    bool
+	slowThreshold  time.Duration
+	maxHosts       int
+	onResult       func(ports.URLResult)
+	callbackMu     sync.Mutex
 	limiter        *rate.Limiter
 }
 
@@ -42,30 +79,53 @@ func New(extractor ports.Extractor, opts ...Option) *Concurrent {
 	return c
 }
 
+// Close は、Extractor が io.Closer を実装している場合にそのリソースを解放します。
+// Extractor を経由して、基盤となる Fetcher (HTTPクライアント) まで解放処理が伝播します。
+func (c *Concurrent) Close() error {
+	if closer, ok := c.extractor.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
 // Run は複数の URL に対して並列スクレイピングを実行します。
 func (c *Concurrent) Run(ctx context.Context, urls []string) []ports.URLResult {
+	specs := make([]ports.URLSpec, len(urls))
+	for i, url := range urls {
+		specs[i] = ports.URLSpec{URL: url}
+	}
+	return c.RunWith(ctx, specs)
+}
+
+// RunWith は URL ごとのリクエスト設定 (User-Agent やヘッダー) を伴って並列スクレイピングを実行します。
+// 各設定は ports.ContextWithURLSpec で Context に格納されて Extractor に渡されます。
+// このモジュールの Fetcher は設定を読み取らないため、設定をリクエストに反映するには
+// ports.URLSpecFromContext を使用する Fetcher を呼び出し側で用意してください。
+func (c *Concurrent) RunWith(ctx context.Context, specs []ports.URLSpec) []ports.URLResult {
 	g, gCtx := errgroup.WithContext(ctx)
 	g.SetLimit(c.maxConcurrency)
 
-	resultsChan := make(chan ports.URLResult, len(urls))
+	resultsChan := make(chan indexedResult, len(specs))
 
-	for _, url := range urls {
-		g.Go(func() error {
-			if err := c.limiter.Wait(gCtx); err != nil {
-				resultsChan <- ports.URLResult{URL: url, Error: err}
-				return nil
-			}
+	targets, skipped := c.filterSpecs(specs)
+	for _, res := range skipped {
+		c.notifyResult(res.result)
+		resultsChan <- res
+	}
 
-			content, hasBodyFound, err := c.extractor.FetchAndExtractText(gCtx, url)
+	var gate *hostGate
+	if c.maxHosts > 0 {
+		gate = newHostGate(c.maxHosts)
+	}
 
-			var extractErr error
-			if err != nil {
-				extractErr = fmt.Errorf("抽出失敗: %w", err)
-			} else if !hasBodyFound {
-				extractErr = fmt.Errorf("URL %s から本文を抽出できませんでした", url)
+	for _, target := range targets {
+		g.Go(func() error {
+			res := c.scrapeGated(gCtx, gate, target.spec)
+			if res.Error == nil {
+				c.RecordSuccess(res.URL)
 			}
-
-			resultsChan <- ports.URLResult{URL: url, Content: content, Error: extractErr}
+			c.notifyResult(res)
+			resultsChan <- indexedResult{index: target.index, result: res}
 			return nil
 		})
 	}
@@ -73,10 +133,214 @@ func (c *Concurrent) Run(ctx context.Context, urls []string) []ports.URLResult {
 	_ = g.Wait()
 	close(resultsChan)
 
-	var finalResults []ports.URLResult
+	completed := make([]indexedResult, 0, len(specs))
 	for res := range resultsChan {
-		finalResults = append(finalResults, res)
+		completed = append(completed, res)
 	}
 
+	return c.orderResults(completed)
+}
+
+// orderResults は、設定された並び順 (ResultOrdering) に従って結果を並べ替えます。
+// completed は完了順に並んでいる必要があります。
+func (c *Concurrent) orderResults(completed []indexedResult) []ports.URLResult {
+	switch c.ordering {
+	case OrderInput:
+		sort.SliceStable(completed, func(i, j int) bool {
+			return completed[i].index < completed[j].index
+		})
+	case OrderScore:
+		sort.SliceStable(completed, func(i, j int) bool {
+			si, sj := resultScore(completed[i].result), resultScore(completed[j].result)
+			if si != sj {
+				return si > sj
+			}
+			return completed[i].index < completed[j].index
+		})
+	}
+
+	var finalResults []ports.URLResult
+	for _, res := range completed {
+		finalResults = append(finalResults, res.result)
+	}
 	return finalResults
 }
+
+// resultScore は結果の品質スコアとして本文の文字数 (rune 数) を返します。
+// エラーとなった結果は常に成功した結果より低いスコアになります。
+func resultScore(res ports.URLResult) int {
+	if res.Error != nil {
+		return -1
+	}
+	return utf8.RuneCountInString(res.Content)
+}
+
+// RecordSuccess は、抽出に成功したURLを設定された SeenStore と CheckpointStore に記録します。
+// ScrapeRunner の逐次リトライのように、Run の外で成功したURLを記録する際にも使用します。
+func (c *Concurrent) RecordSuccess(url string) {
+	if c.seen != nil {
+		c.seen.Mark(url)
+	}
+	if c.checkpoint != nil {
+		c.checkpoint.MarkDone(url)
+	}
+}
+
+// RequestContext は、WithContextHeader で設定したヘッダーを含むリクエスト設定を格納した Context を返します。
+// ScrapeRunner の逐次リトライのように、Run の外で同じ設定のまま取得する際に使用します。
+func (c *Concurrent) RequestContext(ctx context.Context, url string) context.Context {
+	return ports.ContextWithURLSpec(ctx, c.applyContextHeaders(ctx, ports.URLSpec{URL: url}))
+}
+
+// filterSpecs は、ゴルーチンを起動する前に処理対象のURLを絞り込みます。
+// 除外したURLには ports.ErrSkipped をラップしたエラーを設定した結果を返します。
+func (c *Concurrent) filterSpecs(specs []ports.URLSpec) (targets []indexedSpec, skipped []indexedResult) {
+	var completed map[string]bool
+	if c.checkpoint != nil {
+		completed = make(map[string]bool)
+		// フラグメントなどのみが異なるURLを再取得しないよう、正規化したURLで比較します
+		for _, url := range c.checkpoint.Completed() {
+			completed[canonicalKey(url)] = true
+		}
+	}
+
+	perHost := make(map[string]int)
+	for i, spec := range specs {
+		if completed[canonicalKey(spec.URL)] {
+			skipped = append(skipped, indexedResult{index: i, result: ports.URLResult{
+				URL:   spec.URL,
+				Error: fmt.Errorf("URL %s は前回の実行で完了済みのためスキップしました: %w", spec.URL, ports.ErrSkipped),
+			}})
+			continue
+		}
+		if c.seen != nil && c.seen.Seen(spec.URL) {
+			skipped = append(skipped, indexedResult{index: i, result: ports.URLResult{
+				URL:   spec.URL,
+				Error: fmt.Errorf("URL %s は抽出済みのためスキップしました: %w", spec.URL, ports.ErrSkipped),
+			}})
+			continue
+		}
+		if c.maxURLsPerHost > 0 {
+			if host := hostOf(spec.URL); host != "" {
+				if perHost[host] >= c.maxURLsPerHost {
+					skipped = append(skipped, indexedResult{index: i, result: ports.URLResult{
+						URL:   spec.URL,
+						Error: fmt.Errorf("ホスト %s のURL数が上限 (%d件) に達したためスキップしました: %w", host, c.maxURLsPerHost, ports.ErrSkipped),
+					}})
+					continue
+				}
+				perHost[host]++
+			}
+		}
+		targets = append(targets, indexedSpec{index: i, spec: spec})
+	}
+	return targets, skipped
+}
+
+// hostOf はURLのホスト名を小文字で返します。解析できない場合は空文字列を返します。
+func hostOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// scrapeGated は、gate が設定されている場合に同時に処理するホスト数の上限を守って scrapeOne を実行します。
+func (c *Concurrent) scrapeGated(ctx context.Context, gate *hostGate, spec ports.URLSpec) ports.URLResult {
+	if gate == nil {
+		return c.scrapeOne(ctx, spec)
+	}
+
+	host := hostOf(spec.URL)
+	if err := gate.acquire(ctx, host); err != nil {
+		return ports.URLResult{URL: spec.URL, Error: err}
+	}
+	defer gate.release(host)
+	return c.scrapeOne(ctx, spec)
+}
+
+// scrapeOne は単一のURLに対してレート制限の待機と抽出を行い、結果を返します。
+func (c *Concurrent) scrapeOne(ctx context.Context, spec ports.URLSpec) ports.URLResult {
+	url := spec.URL
+	if err := c.limiter.Wait(ctx); err != nil {
+		return ports.URLResult{URL: url, Error: err}
+	}
+	if err := c.waitJitter(ctx); err != nil {
+		return ports.URLResult{URL: url, Error: err}
+	}
+
+	reqCtx := ports.ContextWithURLSpec(ctx, c.applyContextHeaders(ctx, spec))
+	start := time.Now()
+	content, hasBodyFound, err := c.extractor.FetchAndExtractText(reqCtx, url)
+	elapsed := time.Since(start)
+
+	slow := c.slowThreshold > 0 && elapsed > c.slowThreshold
+	if slow {
+		slog.Warn("応答が遅いページを検出しました",
+			slog.String("url", url),
+			slog.Duration("duration", elapsed),
+			slog.Duration("threshold", c.slowThreshold))
+	}
+
+	var extractErr error
+	if err != nil {
+		extractErr = fmt.Errorf("抽出失敗: %w", err)
+	} else if !hasBodyFound && (!c.titleOnlyOK || content == "") {
+		extractErr = fmt.Errorf("URL %s から本文を抽出できませんでした: %w", url, ports.ErrNoContent)
+	}
+
+	return ports.URLResult{URL: url, Content: content, Duration: elapsed, Slow: slow, Error: extractErr}
+}
+
+// applyContextHeaders は、Context の値を設定されたヘッダーとして spec に追加します。
+// 呼び出し元のヘッダーマップを変更しないよう、追加がある場合は複製して返します。
+func (c *Concurrent) applyContextHeaders(ctx context.Context, spec ports.URLSpec) ports.URLSpec {
+	if len(c.contextHeaders) == 0 {
+		return spec
+	}
+
+	headers := make(map[string]string, len(spec.Headers)+len(c.contextHeaders))
+	for name, value := range spec.Headers {
+		headers[name] = value
+	}
+	for _, h := range c.contextHeaders {
+		v := ctx.Value(h.key)
+		if v == nil {
+			continue
+		}
+		if value := fmt.Sprint(v); value != "" {
+			headers[h.name] = value
+		}
+	}
+	spec.Headers = headers
+	return spec
+}
+
+// notifyResult は、設定されたコールバックに完了した結果を通知します。
+// コールバックは同時に複数呼び出されないよう直列化されます。
+func (c *Concurrent) notifyResult(res ports.URLResult) {
+	if c.onResult == nil {
+		return
+	}
+	c.callbackMu.Lock()
+	defer c.callbackMu.Unlock()
+	c.onResult(res)
+}
+
+// waitJitter は、設定されたジッターの範囲でランダムに待機します。Context の終了を考慮します。
+func (c *Concurrent) waitJitter(ctx context.Context) error {
+	if c.fetchJitter <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(rand.N(c.fetchJitter + 1))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -3,12 +3,22 @@ package scraper
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/shouni/go-web-exact/v2/ports"
 )
 
+// Concurrent は ScrapeRunner のリトライ成功を記録できるよう ports.SuccessRecorder を実装するのだ。
+var _ ports.SuccessRecorder = (*Concurrent)(nil)
+
 // mockExtractor はテスト用の Extractor 実装なのだ。
 type mockExtractor struct {
 	fetchFunc func(ctx context.Context, url string) (string, bool, error)
@@ -85,6 +95,9 @@ func TestConcurrent_Run(t *testing.T) {
 		if results[0].Error == nil {
 			t.Error("本文未検出時のエラーが生成されていません")
 		}
+		if !errors.Is(results[0].Error, ports.ErrNoContent) {
+			t.Errorf("本文未検出のエラーが ports.ErrNoContent をラップしていません: %v", results[0].Error)
+		}
 	})
 
 	t.Run("レートリミットの検証: 短時間で大量のリクエストを送った際に時間がかかること", func(t *testing.T) {
@@ -111,3 +124,544 @@ func TestConcurrent_Run(t *testing.T) {
 		}
 	})
 }
+
+// closableExtractor は io.Closer を実装した Extractor のモックなのだ。
+type closableExtractor struct {
+	mockExtractor
+	closed bool
+}
+
+func (m *closableExtractor) Close() error {
+	m.closed = true
+	return nil
+}
+
+func TestConcurrent_Close(t *testing.T) {
+	t.Run("ExtractorがCloserを実装する場合は解放処理が伝播すること", func(t *testing.T) {
+		mock := &closableExtractor{}
+		s := New(mock)
+
+		if err := s.Close(); err != nil {
+			t.Fatalf("予期せぬエラーが発生しました: %v", err)
+		}
+		if !mock.closed {
+			t.Error("ExtractorのCloseが呼ばれていないのだ")
+		}
+	})
+
+	t.Run("Closerを実装しない場合は何もしないこと", func(t *testing.T) {
+		s := New(&mockExtractor{})
+
+		if err := s.Close(); err != nil {
+			t.Fatalf("予期せぬエラーが発生しました: %v", err)
+		}
+	})
+}
+
+func TestConcurrent_FetchDelayJitter(t *testing.T) {
+	t.Run("ジッター待機中にキャンセルされた場合はエラーが返ること", func(t *testing.T) {
+		mock := &mockExtractor{
+			fetchFunc: func(ctx context.Context, url string) (string, bool, error) {
+				return "ok", true, nil
+			},
+		}
+
+		s := New(mock, WithFetchDelayJitter(time.Hour))
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		results := s.Run(ctx, []string{"http://slow.com"})
+
+		if len(results) != 1 || !errors.Is(results[0].Error, context.DeadlineExceeded) {
+			t.Fatalf("ジッター待機中のキャンセルがエラーとして返るべきなのだ: %+v", results)
+		}
+		if atomic.LoadInt32(&mock.callCount) != 0 {
+			t.Error("キャンセル後にExtractorが呼ばれてはいけないのだ")
+		}
+	})
+}
+
+func TestConcurrent_ResultCallback(t *testing.T) {
+	t.Run("完了したURLごとにコールバックが呼ばれること", func(t *testing.T) {
+		mock := &mockExtractor{
+			fetchFunc: func(ctx context.Context, url string) (string, bool, error) {
+				return "content for " + url, true, nil
+			},
+		}
+
+		// コールバックは直列化されるため、ロックなしで書き込めるのだ
+		received := make(map[string]string)
+		s := New(mock,
+			WithMaxConcurrency(3),
+			WithRateLimit(time.Millisecond),
+			WithResultCallback(func(res ports.URLResult) {
+				received[res.URL] = res.Content
+			}),
+		)
+		urls := []string{"http://example.com/1", "http://example.com/2", "http://example.com/3"}
+
+		_ = s.Run(context.Background(), urls)
+
+		if len(received) != len(urls) {
+			t.Fatalf("コールバックは %d 回呼ばれるべきですが、%d 件でした", len(urls), len(received))
+		}
+		for _, url := range urls {
+			if received[url] != "content for "+url {
+				t.Errorf("URL %s の結果が通知されていません", url)
+			}
+		}
+	})
+}
+
+func TestConcurrent_RunWith(t *testing.T) {
+	t.Run("URLごとのリクエスト設定がContext経由で引き渡されること", func(t *testing.T) {
+		var mu sync.Mutex
+		got := make(map[string]ports.URLSpec)
+		mock := &mockExtractor{
+			fetchFunc: func(ctx context.Context, url string) (string, bool, error) {
+				spec, ok := ports.URLSpecFromContext(ctx)
+				if !ok {
+					return "", false, errors.New("URLSpec not found")
+				}
+				mu.Lock()
+				got[url] = spec
+				mu.Unlock()
+				return "ok", true, nil
+			},
+		}
+
+		s := New(mock, WithRateLimit(time.Millisecond))
+		specs := []ports.URLSpec{
+			{URL: "http://a.com", UserAgent: "agent-a"},
+			{URL: "http://b.com", Headers: map[string]string{"Accept-Language": "ja"}},
+		}
+
+		results := s.RunWith(context.Background(), specs)
+
+		for _, res := range results {
+			if res.Error != nil {
+				t.Fatalf("URL %s で予期せぬエラーが発生しました: %v", res.URL, res.Error)
+			}
+		}
+		if got["http://a.com"].UserAgent != "agent-a" {
+			t.Errorf("User-Agentが引き渡されていません: %+v", got["http://a.com"])
+		}
+		if got["http://b.com"].Headers["Accept-Language"] != "ja" {
+			t.Errorf("ヘッダーが引き渡されていません: %+v", got["http://b.com"])
+		}
+	})
+}
+
+type requestIDKey struct{}
+
+func TestConcurrent_ContextHeader(t *testing.T) {
+	newMock := func(got *sync.Map) *mockExtractor {
+		return &mockExtractor{
+			fetchFunc: func(ctx context.Context, url string) (string, bool, error) {
+				spec, _ := ports.URLSpecFromContext(ctx)
+				got.Store(url, spec.Headers)
+				return "ok", true, nil
+			},
+		}
+	}
+
+	t.Run("Contextの値がヘッダーとして引き渡されること", func(t *testing.T) {
+		var got sync.Map
+		s := New(newMock(&got), WithContextHeader(requestIDKey{}, "X-Request-ID"))
+		ctx := context.WithValue(context.Background(), requestIDKey{}, "req-123")
+
+		_ = s.RunWith(ctx, []ports.URLSpec{{URL: "http://a.com", Headers: map[string]string{"Accept": "text/html"}}})
+
+		v, _ := got.Load("http://a.com")
+		headers := v.(map[string]string)
+		if headers["X-Request-ID"] != "req-123" || headers["Accept"] != "text/html" {
+			t.Errorf("ヘッダーが正しく引き渡されていないのだ: %v", headers)
+		}
+	})
+
+	t.Run("Contextに値が無い場合はヘッダーを付与しないこと", func(t *testing.T) {
+		var got sync.Map
+		s := New(newMock(&got), WithContextHeader(requestIDKey{}, "X-Request-ID"))
+
+		_ = s.Run(context.Background(), []string{"http://a.com"})
+
+		v, _ := got.Load("http://a.com")
+		if _, ok := v.(map[string]string)["X-Request-ID"]; ok {
+			t.Error("値が無い場合はヘッダーを付与しないべきなのだ")
+		}
+	})
+}
+
+func TestConcurrent_MaxURLsPerHost(t *testing.T) {
+	t.Run("同一ホストの上限を超えたURLはスキップされること", func(t *testing.T) {
+		mock := &mockExtractor{
+			fetchFunc: func(ctx context.Context, url string) (string, bool, error) {
+				return "ok", true, nil
+			},
+		}
+
+		s := New(mock, WithRateLimit(time.Millisecond), WithMaxURLsPerHost(2))
+		urls := []string{
+			"http://a.com/1",
+			"http://A.com/2",
+			"http://a.com/3",
+			"http://b.com/1",
+		}
+
+		results := s.Run(context.Background(), urls)
+
+		if len(results) != len(urls) {
+			t.Fatalf("全URL分の結果が返るべきなのだ。got: %d", len(results))
+		}
+		for _, res := range results {
+			skipped := errors.Is(res.Error, ports.ErrSkipped)
+			if res.URL == "http://a.com/3" && !skipped {
+				t.Errorf("上限を超えたURLはスキップされるべきなのだ: %+v", res)
+			}
+			if res.URL != "http://a.com/3" && res.Error != nil {
+				t.Errorf("URL %s で予期せぬエラーが発生しました: %v", res.URL, res.Error)
+			}
+		}
+		if atomic.LoadInt32(&mock.callCount) != 3 {
+			t.Errorf("スキップしたURLは取得しないべきなのだ: %d", mock.callCount)
+		}
+	})
+}
+
+func TestConcurrent_ResultOrdering(t *testing.T) {
+	// 入力順とは逆の順序で完了するよう、後ろのURLほど早く返すのだ
+	delays := map[string]time.Duration{
+		"http://a.com": 60 * time.Millisecond,
+		"http://b.com": 30 * time.Millisecond,
+		"http://c.com": 0,
+	}
+	contents := map[string]string{
+		"http://a.com": "short",
+		"http://b.com": "much longer content",
+	}
+	newMock := func() *mockExtractor {
+		return &mockExtractor{
+			fetchFunc: func(ctx context.Context, url string) (string, bool, error) {
+				time.Sleep(delays[url])
+				if url == "http://c.com" {
+					return "", false, errors.New("fail")
+				}
+				return contents[url], true, nil
+			},
+		}
+	}
+	urls := []string{"http://a.com", "http://b.com", "http://c.com"}
+	collect := func(results []ports.URLResult) []string {
+		var got []string
+		for _, res := range results {
+			got = append(got, res.URL)
+		}
+		return got
+	}
+
+	testCases := []struct {
+		name     string
+		opts     []Option
+		expected []string
+	}{
+		{name: "デフォルトは入力順", expected: []string{"http://a.com", "http://b.com", "http://c.com"}},
+		{name: "完了順", opts: []Option{WithResultOrdering(OrderCompletion)}, expected: []string{"http://c.com", "http://b.com", "http://a.com"}},
+		{name: "スコア順", opts: []Option{WithResultOrdering(OrderScore)}, expected: []string{"http://b.com", "http://a.com", "http://c.com"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := append([]Option{WithRateLimit(time.Millisecond)}, tc.opts...)
+			s := New(newMock(), opts...)
+
+			got := collect(s.Run(context.Background(), urls))
+
+			if strings.Join(got, ",") != strings.Join(tc.expected, ",") {
+				t.Errorf("並び順が期待と異なるのだ。got: %v, want: %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestConcurrent_SeenStore(t *testing.T) {
+	t.Run("抽出済みのURLはスキップされ、成功したURLが記録されること", func(t *testing.T) {
+		mock := &mockExtractor{
+			fetchFunc: func(ctx context.Context, url string) (string, bool, error) {
+				if url == "http://example.com/fail" {
+					return "", false, errors.New("network error")
+				}
+				return "ok", true, nil
+			},
+		}
+
+		store := NewMemorySeenStore()
+		store.Mark("http://example.com/old")
+		s := New(mock, WithRateLimit(time.Millisecond), WithSeenStore(store))
+
+		results := s.Run(context.Background(), []string{
+			"http://example.com/old",
+			"http://example.com/new",
+			"http://example.com/fail",
+		})
+
+		if !errors.Is(results[0].Error, ports.ErrSkipped) {
+			t.Errorf("抽出済みのURLはスキップされるべきなのだ: %+v", results[0])
+		}
+		if results[1].Error != nil {
+			t.Errorf("未処理のURLは抽出されるべきなのだ: %v", results[1].Error)
+		}
+		if atomic.LoadInt32(&mock.callCount) != 2 {
+			t.Errorf("スキップしたURLは取得しないべきなのだ: %d", mock.callCount)
+		}
+		if !store.Seen("http://example.com/new") {
+			t.Error("成功したURLはストアに記録されるべきなのだ")
+		}
+		if store.Seen("http://example.com/fail") {
+			t.Error("失敗したURLは次回再試行できるよう記録しないべきなのだ")
+		}
+	})
+	t.Run("正規化したURLで既読判定されること", func(t *testing.T) {
+		store := NewMemorySeenStore()
+		store.Mark("https://Example.com/post?utm_source=feed#top")
+
+		if !store.Seen("https://example.com/post") {
+			t.Error("トラッキングパラメーターやフラグメントのみが異なるURLは既読とみなすべきなのだ")
+		}
+		if store.Seen("https://example.com/other") {
+			t.Error("異なるURLは既読とみなさないべきなのだ")
+		}
+	})
+}
+
+func TestConcurrent_TreatTitleOnlyAsSuccess(t *testing.T) {
+	mock := &mockExtractor{
+		fetchFunc: func(ctx context.Context, url string) (string, bool, error) {
+			return "【記事タイトル】 Index", false, nil
+		},
+	}
+
+	t.Run("有効な場合はタイトルのみの結果が成功になること", func(t *testing.T) {
+		s := New(mock, WithTreatTitleOnlyAsSuccess(true))
+
+		results := s.Run(context.Background(), []string{"http://example.com/index"})
+
+		if results[0].Error != nil {
+			t.Errorf("タイトルのみの結果は成功として扱われるべきなのだ: %v", results[0].Error)
+		}
+		if results[0].Content != "【記事タイトル】 Index" {
+			t.Errorf("タイトルがContentに設定されるべきなのだ: %q", results[0].Content)
+		}
+	})
+
+	t.Run("デフォルトではタイトルのみの結果はエラーになること", func(t *testing.T) {
+		s := New(mock)
+
+		results := s.Run(context.Background(), []string{"http://example.com/index"})
+
+		if results[0].Error == nil {
+			t.Error("本文が無い結果はエラーになるべきなのだ")
+		}
+	})
+}
+
+func TestConcurrent_SlowThreshold(t *testing.T) {
+	mock := &mockExtractor{
+		fetchFunc: func(ctx context.Context, url string) (string, bool, error) {
+			if url == "http://example.com/slow" {
+				time.Sleep(50 * time.Millisecond)
+			}
+			return "ok", true, nil
+		},
+	}
+
+	t.Run("閾値を超えたページが低速として記録されること", func(t *testing.T) {
+		s := New(mock, WithRateLimit(time.Millisecond), WithSlowThreshold(20*time.Millisecond))
+
+		results := s.Run(context.Background(), []string{"http://example.com/fast", "http://example.com/slow"})
+
+		if results[0].Slow {
+			t.Errorf("速いページは低速として記録しないべきなのだ: %v", results[0].Duration)
+		}
+		if !results[1].Slow || results[1].Error != nil {
+			t.Errorf("遅いページは成功かつ低速として記録されるべきなのだ: %+v", results[1])
+		}
+		if results[1].Duration < 50*time.Millisecond {
+			t.Errorf("処理時間が記録されるべきなのだ: %v", results[1].Duration)
+		}
+	})
+
+	t.Run("閾値が未設定の場合は低速判定しないこと", func(t *testing.T) {
+		s := New(mock, WithRateLimit(time.Millisecond))
+
+		results := s.Run(context.Background(), []string{"http://example.com/slow"})
+
+		if results[0].Slow {
+			t.Error("閾値が未設定の場合は低速として記録しないべきなのだ")
+		}
+		if results[0].Duration <= 0 {
+			t.Error("閾値に関わらず処理時間は記録されるべきなのだ")
+		}
+	})
+}
+
+func TestConcurrent_MaxConcurrentHosts(t *testing.T) {
+	t.Run("同時に処理するホスト数が上限を超えないこと", func(t *testing.T) {
+		var mu sync.Mutex
+		active := make(map[string]int)
+		maxHosts := 0
+
+		mock := &mockExtractor{
+			fetchFunc: func(ctx context.Context, url string) (string, bool, error) {
+				host := hostOf(url)
+				mu.Lock()
+				active[host]++
+				maxHosts = max(maxHosts, len(active))
+				mu.Unlock()
+
+				time.Sleep(10 * time.Millisecond)
+
+				mu.Lock()
+				if active[host]--; active[host] == 0 {
+					delete(active, host)
+				}
+				mu.Unlock()
+				return "ok", true, nil
+			},
+		}
+
+		s := New(mock, WithRateLimit(time.Microsecond), WithMaxConcurrency(8), WithMaxConcurrentHosts(2))
+		var urls []string
+		for _, host := range []string{"a.com", "b.com", "c.com", "d.com"} {
+			for i := range 3 {
+				urls = append(urls, fmt.Sprintf("http://%s/%d", host, i))
+			}
+		}
+
+		results := s.Run(context.Background(), urls)
+
+		for _, res := range results {
+			if res.Error != nil {
+				t.Errorf("URL %s で予期せぬエラーが発生しました: %v", res.URL, res.Error)
+			}
+		}
+		if maxHosts > 2 {
+			t.Errorf("同時に処理するホスト数は2以下であるべきなのだ: %d", maxHosts)
+		}
+	})
+
+	t.Run("待機中にキャンセルされた場合はエラーが返ること", func(t *testing.T) {
+		gate := newHostGate(1)
+		if err := gate.acquire(context.Background(), "a.com"); err != nil {
+			t.Fatal(err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		if err := gate.acquire(ctx, "b.com"); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("上限到達中の別ホストは待機し、キャンセルでエラーになるべきなのだ: %v", err)
+		}
+
+		gate.release("a.com")
+		if err := gate.acquire(context.Background(), "b.com"); err != nil {
+			t.Errorf("解放後は別ホストを処理できるべきなのだ: %v", err)
+		}
+	})
+}
+
+func TestConcurrent_CheckpointStore(t *testing.T) {
+	t.Run("中断した実行を再開した場合に完了済みのURLがスキップされること", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "checkpoint.txt")
+		failing := map[string]bool{"http://example.com/2": true}
+		mock := &mockExtractor{
+			fetchFunc: func(ctx context.Context, url string) (string, bool, error) {
+				if failing[url] {
+					return "", false, errors.New("network error")
+				}
+				return "ok", true, nil
+			},
+		}
+		urls := []string{"http://example.com/1", "http://example.com/2", "http://example.com/3"}
+
+		// 1回目の実行: /2 のみ失敗するのだ
+		store, err := NewFileCheckpointStore(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		New(mock, WithRateLimit(time.Millisecond), WithCheckpointStore(store)).Run(context.Background(), urls)
+		if err := store.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		// 2回目の実行: ファイルから進捗を復元するのだ
+		failing = map[string]bool{}
+		atomic.StoreInt32(&mock.callCount, 0)
+		store, err = NewFileCheckpointStore(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer store.Close()
+		results := New(mock, WithRateLimit(time.Millisecond), WithCheckpointStore(store)).Run(context.Background(), urls)
+
+		if !errors.Is(results[0].Error, ports.ErrSkipped) || !errors.Is(results[2].Error, ports.ErrSkipped) {
+			t.Errorf("完了済みのURLはスキップされるべきなのだ: %+v", results)
+		}
+		if results[1].Error != nil {
+			t.Errorf("前回失敗したURLは再取得されるべきなのだ: %v", results[1].Error)
+		}
+		if atomic.LoadInt32(&mock.callCount) != 1 {
+			t.Errorf("未完了のURLのみ取得するべきなのだ: %d", mock.callCount)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Count(string(data), "\n"); got != 3 {
+			t.Errorf("完了したURLが1行ずつ記録されるべきなのだ: %q", data)
+		}
+	})
+
+	t.Run("正規化すると同じURLは完了済みとしてスキップされること", func(t *testing.T) {
+		store, err := NewFileCheckpointStore(filepath.Join(t.TempDir(), "checkpoint.txt"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer store.Close()
+		store.MarkDone("https://example.com/post")
+
+		mock := &mockExtractor{
+			fetchFunc: func(ctx context.Context, url string) (string, bool, error) {
+				return "ok", true, nil
+			},
+		}
+		results := New(mock, WithRateLimit(time.Millisecond), WithCheckpointStore(store)).Run(context.Background(),
+			[]string{"https://EXAMPLE.com/post#comments", "https://example.com/post?utm_source=feed"})
+
+		for _, res := range results {
+			if !errors.Is(res.Error, ports.ErrSkipped) {
+				t.Errorf("正規化すると完了済みのURLはスキップされるべきなのだ: %+v", res)
+			}
+		}
+		if atomic.LoadInt32(&mock.callCount) != 0 {
+			t.Errorf("完了済みのURLは取得するべきではないのだ: %d", mock.callCount)
+		}
+	})
+
+	t.Run("RecordSuccess はストアに記録すること", func(t *testing.T) {
+		store, err := NewFileCheckpointStore(filepath.Join(t.TempDir(), "checkpoint.txt"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer store.Close()
+		seen := NewMemorySeenStore()
+
+		New(&mockExtractor{}, WithCheckpointStore(store), WithSeenStore(seen)).RecordSuccess("https://example.com/retried")
+
+		if got := store.Completed(); len(got) != 1 || got[0] != "https://example.com/retried" {
+			t.Errorf("チェックポイントに記録されるべきなのだ: %v", got)
+		}
+		if !seen.Seen("https://example.com/retried") {
+			t.Error("抽出済みとして記録されるべきなのだ")
+		}
+	})
+}
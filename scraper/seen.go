@@ -0,0 +1,44 @@
+package scraper
+
+import (
+	"sync"
+
+	"github.com/shouni/go-web-exact/v2/urlutil"
+)
+
+// MemorySeenStore は、抽出済みURLをメモリ上に保持する ports.SeenStore の実装です。
+// URLは urlutil.Canonicalize で正規化して比較するため、トラッキングパラメーターやフラグメントのみが
+// 異なるURLは同一とみなされます。プロセスの終了とともに記録は失われます。永続化が必要な場合は独自の実装を用意してください。
+type MemorySeenStore struct {
+	mu   sync.RWMutex
+	urls map[string]struct{}
+}
+
+// NewMemorySeenStore は空の MemorySeenStore を生成します。
+func NewMemorySeenStore() *MemorySeenStore {
+	return &MemorySeenStore{urls: make(map[string]struct{})}
+}
+
+// Seen は、URLが記録済みの場合に true を返します。
+func (s *MemorySeenStore) Seen(url string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.urls[canonicalKey(url)]
+	return ok
+}
+
+// Mark は、URLを抽出済みとして記録します。
+func (s *MemorySeenStore) Mark(url string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.urls[canonicalKey(url)] = struct{}{}
+}
+
+// canonicalKey は、SeenStore や CheckpointStore の記録の比較に用いるキーを返します。
+// 正規化できないURLはそのまま使用します。
+func canonicalKey(url string) string {
+	if canonical, err := urlutil.Canonicalize(url); err == nil {
+		return canonical
+	}
+	return url
+}
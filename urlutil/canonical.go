@@ -0,0 +1,114 @@
+// Package urlutil は、複数の機能で共通して利用するURLの正規化処理を提供します。
+package urlutil
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// DefaultTrackingParams は、Canonicalize が除去するトラッキング用のクエリパラメーターです。
+// 末尾が "*" の項目は前方一致で比較します。
+var DefaultTrackingParams = []string{
+	"utm_*",
+	"fbclid",
+	"gclid",
+	"dclid",
+	"msclkid",
+	"yclid",
+	"mc_cid",
+	"mc_eid",
+	"_ga",
+	"igshid",
+}
+
+// defaultPorts はスキームごとの既定のポート番号です。
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+// Canonicalize は、DefaultTrackingParams を除去対象として CanonicalizeWith を呼び出します。
+// 重複排除、既読判定、キャッシュなど、URLの同一性を判定する機能はこの関数で正規化したURLを比較します。
+func Canonicalize(raw string) (string, error) {
+	return CanonicalizeWith(raw, DefaultTrackingParams)
+}
+
+// CanonicalizeWith は、URLを比較可能な正規形に変換します。
+//   - スキームとホスト名を小文字にし、既定のポート番号を除去します
+//   - パスの "." と ".." を解決し、空のパスは "/" にします
+//   - trackingParams に一致するクエリパラメーターを除去し、残りをキーの順に並べます
+//   - フラグメントを除去します
+//
+// 絶対URLとして解釈できない場合はエラーを返します。
+func CanonicalizeWith(raw string, trackingParams []string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", fmt.Errorf("URLの解析に失敗しました: %w", err)
+	}
+	if !u.IsAbs() || u.Host == "" {
+		return "", fmt.Errorf("絶対URLではありません: %s", raw)
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Hostname())
+	if port := u.Port(); port != "" && port != defaultPorts[u.Scheme] {
+		host = joinHostPort(host, port)
+	} else if strings.Contains(host, ":") {
+		host = "[" + host + "]" // IPv6 アドレス
+	}
+	u.Host = host
+
+	u.Path = cleanPath(u.Path)
+	u.RawPath = ""
+
+	query := u.Query()
+	for key := range query {
+		if isTrackingParam(key, trackingParams) {
+			query.Del(key)
+		}
+	}
+	u.RawQuery = query.Encode() // Encode はキーの順に並べ替えます
+	u.ForceQuery = false
+
+	u.Fragment = ""
+	u.RawFragment = ""
+	return u.String(), nil
+}
+
+// cleanPath はパスのドットセグメントを解決します。末尾のスラッシュは保持します。
+func cleanPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	cleaned := path.Clean("/" + p)
+	if strings.HasSuffix(p, "/") && cleaned != "/" {
+		cleaned += "/"
+	}
+	return cleaned
+}
+
+// joinHostPort は IPv6 アドレスを角括弧で囲んでポート番号と連結します。
+func joinHostPort(host, port string) string {
+	if strings.Contains(host, ":") {
+		return "[" + host + "]:" + port
+	}
+	return host + ":" + port
+}
+
+// isTrackingParam は、クエリパラメーター名が除去対象に一致するかを判定します。
+func isTrackingParam(key string, trackingParams []string) bool {
+	key = strings.ToLower(key)
+	for _, param := range trackingParams {
+		param = strings.ToLower(param)
+		if prefix, ok := strings.CutSuffix(param, "*"); ok {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		} else if key == param {
+			return true
+		}
+	}
+	return false
+}
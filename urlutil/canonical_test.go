@@ -0,0 +1,53 @@
+package urlutil_test
+
+import (
+	"testing"
+
+	"github.com/shouni/go-web-exact/v2/urlutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonicalize(t *testing.T) {
+	testCases := []struct {
+		name     string
+		raw      string
+		expected string
+	}{
+		{name: "lowercase_scheme_and_host", raw: "HTTPS://Example.COM/Path", expected: "https://example.com/Path"},
+		{name: "default_port_removed", raw: "http://example.com:80/a", expected: "http://example.com/a"},
+		{name: "non_default_port_kept", raw: "https://example.com:8443/a", expected: "https://example.com:8443/a"},
+		{name: "ipv6_host", raw: "http://[::1]:80/a", expected: "http://[::1]/a"},
+		{name: "empty_path", raw: "https://example.com", expected: "https://example.com/"},
+		{name: "dot_segments", raw: "https://example.com/a/./b/../c/", expected: "https://example.com/a/c/"},
+		{name: "query_sorted", raw: "https://example.com/?b=2&a=1", expected: "https://example.com/?a=1&b=2"},
+		{
+			name:     "tracking_params_removed",
+			raw:      "https://example.com/post?utm_source=x&UTM_Medium=y&id=7&fbclid=z",
+			expected: "https://example.com/post?id=7",
+		},
+		{name: "only_tracking_params", raw: "https://example.com/post?gclid=1", expected: "https://example.com/post"},
+		{name: "fragment_removed", raw: "https://example.com/post#comments", expected: "https://example.com/post"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := urlutil.Canonicalize(tc.raw)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+
+	t.Run("relative_url_is_error", func(t *testing.T) {
+		_, err := urlutil.Canonicalize("/relative/path")
+
+		assert.Error(t, err)
+	})
+}
+
+func TestCanonicalizeWith(t *testing.T) {
+	got, err := urlutil.CanonicalizeWith("https://example.com/?ref=feed&utm_source=x", []string{"ref"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com/?utm_source=x", got)
+}